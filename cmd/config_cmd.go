@@ -3,6 +3,7 @@ package main
 import (
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/go-playground/validator"
 	"github.com/pkg/errors"
@@ -21,6 +22,7 @@ func init() {
 		fileName   string
 		sourceApp  string
 		readerType string
+		timeout    time.Duration
 	)
 
 	showAppCmd := &cobra.Command{
@@ -60,6 +62,9 @@ func init() {
 					return errors.New(fmt.Sprintf("Project with name '%s' already exists", config.ProjectName))
 				}
 			}
+			if !sqlrog.IsGeneratorValuesPolicy(config.GeneratorValues) {
+				return errors.New(fmt.Sprintf("Unknown generator values policy '%s'", config.GeneratorValues))
+			}
 			if config.AppType == sqlrog.ProjectTypeFile {
 				if sourceApp == "" {
 					return errors.New("Source connection app should be set")
@@ -83,8 +88,10 @@ func init() {
 					Source:   sourceApp,
 					FileType: readerType,
 				}
-				sourceConfig := sqlrog.ProjectConfig.Projects[sourceApp]
-				schema, err := engine.LoadSchema(sourceConfig, &sqlrog.YamlSchemaReader{})
+				sourceConfig := sqlrog.ProjectConfig.Projects[sourceApp].WithData(config.Data).WithGrants(config.Grants).WithAutoIncrement(config.AutoIncrement).WithDefiner(config.Definer).WithGeneratorValues(config.GeneratorValues)
+				ctx, cancel := commandContext(timeout)
+				defer cancel()
+				schema, err := engine.LoadSchema(ctx, sourceConfig, &sqlrog.YamlSchemaReader{}, nil)
 				if err != nil {
 					return err
 				}
@@ -127,6 +134,13 @@ func init() {
 	addAppCmd.Flags().StringVarP(&readerType, "readertype", "r", "yml", "Schema reader type (default is yml)")
 	addAppCmd.Flags().StringVarP(&sourceApp, "source", "s", "", "Source connection App")
 	addAppCmd.Flags().StringVarP(&fileName, "config", "c", sqlrog.DefaultConfigFileName, "Config file name")
+	addAppCmd.Flags().StringSliceVar(&config.Data, "data", []string{}, "Tables which data is tracked by the file project")
+	addAppCmd.Flags().BoolVar(&config.Grants, "grants", false, "Track grants of users and roles in the project")
+	addAppCmd.Flags().BoolVar(&config.AutoIncrement, "auto-increment", false, "Track auto-increment counters of tables in the project")
+	addAppCmd.Flags().StringVar(&config.Definer, "definer", "", "Definer (user@host) of views, routines and events in the project")
+	addAppCmd.Flags().BoolVar(&config.Statistics, "statistics", false, "Recompute Firebird index statistics after data changes are applied")
+	addAppCmd.Flags().StringVar(&config.GeneratorValues, "generator-values", "", "Sync Firebird generator values with policy raise/max/exact")
+	addAppCmd.Flags().DurationVar(&timeout, "timeout", 0, "Timeout for reading the source schema (e.g. 30s, 5m)")
 	showAppCmd.Flags().StringVarP(&fileName, "config", "c", sqlrog.DefaultConfigFileName, "Config file name")
 
 	CliCommands = append(CliCommands, addAppCmd, showAppCmd)
@@ -1,8 +1,9 @@
 package main
 
 import (
+	"fmt"
 	"sort"
-	"strings"
+	"time"
 
 	"github.com/fatih/color"
 
@@ -18,7 +19,9 @@ func init() {
 		source   string
 		target   string
 		filter   string
+		ignore   []string
 		apply    bool
+		timeout  time.Duration
 	)
 	diffCmd := &cobra.Command{
 		Use:           "diff",
@@ -47,6 +50,23 @@ func init() {
 				return errors.New("Source and target app engines should be compatible.")
 			}
 			engine := sqlrog.Engines[sourceApp.Engine]
+			ctx, cancel := commandContext(timeout)
+			defer cancel()
+			elementFilter := &sqlrog.ElementFilter{
+				Match:  filter,
+				Ignore: ignore,
+			}
+			dataTables := sqlrog.MergeDataTables(sourceApp.Data, targetApp.Data)
+			// Grants differ between environments, so they are compared only when both projects track them
+			grants := sourceApp.Grants && targetApp.Grants
+			autoIncrement := sourceApp.AutoIncrement && targetApp.AutoIncrement
+			// Definer of the target environment replaces source definers, target keeps its own to show the drift
+			definer := targetApp.Definer
+			// Generator values are captured when both projects opt in, the target decides how they are synced
+			var generatorValues string
+			if sourceApp.GeneratorValues != "" {
+				generatorValues = targetApp.GeneratorValues
+			}
 			type chanResult struct {
 				Schema sqlrog.ElementSchema
 				Error  error
@@ -55,7 +75,7 @@ func init() {
 			targetChan := make(chan chanResult)
 			go func() {
 				sqlrog.Logln("info", "Fetching source schema...")
-				sourceSchema, err := engine.LoadSchema(sourceApp, &sqlrog.YamlSchemaReader{})
+				sourceSchema, err := engine.LoadSchema(ctx, sourceApp.WithData(dataTables).WithGrants(grants).WithAutoIncrement(autoIncrement).WithDefiner(definer).WithGeneratorValues(generatorValues), &sqlrog.YamlSchemaReader{}, elementFilter)
 				sourceChan <- chanResult{
 					Schema: sourceSchema,
 					Error:  err,
@@ -63,7 +83,7 @@ func init() {
 			}()
 			go func() {
 				sqlrog.Logln("info", "Fetching target schema...")
-				targetSchema, err := engine.LoadSchema(targetApp, &sqlrog.YamlSchemaReader{})
+				targetSchema, err := engine.LoadSchema(ctx, targetApp.WithData(dataTables).WithGrants(grants).WithAutoIncrement(autoIncrement).WithDefiner("").WithGeneratorValues(generatorValues), &sqlrog.YamlSchemaReader{}, elementFilter)
 				targetChan <- chanResult{
 					Schema: targetSchema,
 					Error:  err,
@@ -88,30 +108,40 @@ func init() {
 			green := color.New(color.FgHiGreen)
 			yellow := color.New(color.FgYellow)
 
-			diffs = applyFilter(filter, diffs)
-
 			if len(diffs) == 0 {
 				sqlrog.Logln("warn", "There is nothing to change")
 			} else {
 				sort.Slice(diffs, func(i, j int) bool {
 					return diffs[i].Priority > diffs[j].Priority
 				})
+				// hooks of the source project surround the changes and are skipped when files are updated
+				if diffs, err = sqlrog.AttachHooks(engine, sourceApp, sourceSchema, diffs); err != nil {
+					return err
+				}
 				if apply {
-					if err = engine.ApplyDiffs(targetApp, diffs, sqlrog.DEFAULT_SQL_SEP_WITH_RETURN); err != nil {
+					if err = engine.ApplyDiffs(ctx, targetApp, diffs, sqlrog.DEFAULT_SQL_SEP_WITH_RETURN); err != nil {
 						return err
 					}
 				} else {
 					sqlrog.Logln("info", "Diff SQL:")
+					// the script is rendered for the native client, so it could be saved and run as is
+					renderer := engine.ScriptRenderer()
 					for _, change := range diffs {
+						stmts := change.DiffSql("")
+						if len(stmts) == 0 {
+							continue
+						}
+						script := renderer.Statements(stmts)
 						switch change.State {
 						case sqlrog.DIFF_TYPE_DROP:
-							red.Printf("%s\n", strings.Join(change.DiffSql(sqlrog.DEFAULT_SQL_SEP), ""))
+							red.Printf("%s\n", script)
 						case sqlrog.DIFF_TYPE_CREATE:
-							green.Printf("%s\n", strings.Join(change.DiffSql(sqlrog.DEFAULT_SQL_SEP), ""))
+							green.Printf("%s\n", script)
 						case sqlrog.DIFF_TYPE_UPDATE:
-							yellow.Printf("%s\n", strings.Join(change.DiffSql(sqlrog.DEFAULT_SQL_SEP), ""))
+							yellow.Printf("%s\n", script)
 						}
 					}
+					fmt.Print(renderer.Finish())
 				}
 			}
 
@@ -120,10 +150,12 @@ func init() {
 	}
 
 	diffCmd.Flags().StringVarP(&filter, "filter", "f", "", "Filter by element name")
+	diffCmd.Flags().StringSliceVarP(&ignore, "ignore", "i", []string{}, "Ignore elements which names contain the text")
 	diffCmd.Flags().StringVarP(&source, "source", "s", "", "Source project")
 	diffCmd.Flags().StringVarP(&target, "target", "t", "", "Target project")
 	diffCmd.Flags().BoolVarP(&apply, "apply", "a", false, "Apply changes for target")
 	diffCmd.Flags().StringVarP(&fileName, "config", "c", sqlrog.DefaultConfigFileName, "Config file name")
+	diffCmd.Flags().DurationVar(&timeout, "timeout", 0, "Timeout for loading schemas and applying changes (e.g. 30s, 5m)")
 
 	CliCommands = append(CliCommands, diffCmd)
 }
@@ -134,19 +166,3 @@ func compareApps(engine sqlrog.Engine, sourceSchema sqlrog.ElementSchema, target
 
 	return changes, nil
 }
-
-func applyFilter(filter string, diffs []*sqlrog.DiffObject) []*sqlrog.DiffObject {
-	if filter == "" {
-		return diffs
-	}
-	filter = strings.ToUpper(filter)
-	var newDiffs []*sqlrog.DiffObject
-	for _, diff := range diffs {
-		if (diff.To != nil && strings.Contains(strings.ToUpper(diff.To.GetName()), filter)) ||
-			(diff.From != nil && strings.Contains(strings.ToUpper(diff.From.GetName()), filter)) {
-			newDiffs = append(newDiffs, diff)
-		}
-	}
-
-	return newDiffs
-}
@@ -0,0 +1,99 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"github.com/stpatrickw/sqlrog/internal/sqlrog"
+)
+
+func init() {
+	var (
+		fileName string
+		target   string
+		timeout  time.Duration
+	)
+	execCmd := &cobra.Command{
+		Use:           "exec [flags] file.sql...",
+		Short:         "Execute SQL scripts",
+		Long:          "Run scripts of the native client (DELIMITER, SET TERM) on a connection project",
+		Args:          cobra.MinimumNArgs(1),
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := sqlrog.ProjectConfig.Load(fileName); err != nil {
+				return err
+			}
+			config, ok := sqlrog.ProjectConfig.Projects[target]
+			if !ok {
+				return errors.New("Target app is not found")
+			}
+			if config.AppType == sqlrog.ProjectTypeFile {
+				return errors.New("Scripts can be executed only on connection projects")
+			}
+			engine := sqlrog.Engines[config.Engine]
+
+			// all scripts are parsed before the first statement runs, so a broken script changes nothing
+			scripts := make([][]*sqlrog.ScriptStatement, len(args))
+			for i, scriptName := range args {
+				data, err := ioutil.ReadFile(scriptName)
+				if err != nil {
+					return err
+				}
+				scripts[i], err = engine.ScriptParser().Split(string(data))
+				if err != nil {
+					return errors.Wrap(err, scriptName)
+				}
+			}
+
+			ctx, cancel := commandContext(timeout)
+			defer cancel()
+			executed := 0
+			for i, statements := range scripts {
+				count, err := executeScript(ctx, engine, config, args[i], statements)
+				executed += count
+				if err != nil {
+					if ctx.Err() != nil {
+						sqlrog.Logln("warn", fmt.Sprintf("Execution is cancelled after %d statement(s)", executed))
+					}
+					return err
+				}
+			}
+			sqlrog.Logln("info", fmt.Sprintf("%d statement(s) executed", executed))
+
+			return nil
+		},
+	}
+	execCmd.Flags().StringVarP(&target, "target", "t", "", "Target project")
+	execCmd.Flags().StringVarP(&fileName, "config", "c", sqlrog.DefaultConfigFileName, "Config file name")
+	execCmd.Flags().DurationVar(&timeout, "timeout", 0, "Timeout for executing scripts (e.g. 30s, 5m)")
+
+	CliCommands = append(CliCommands, execCmd)
+}
+
+// executeScript runs statements of the script in one call, so they share the session like statements of a diff.
+// It returns the number of executed statements.
+func executeScript(ctx context.Context, engine sqlrog.Engine, config *sqlrog.Config, scriptName string, statements []*sqlrog.ScriptStatement) (int, error) {
+	if len(statements) == 0 {
+		return 0, nil
+	}
+	sqls := make([]string, len(statements))
+	sqlrog.Logln("info", fmt.Sprintf("Executing %s: ...", scriptName))
+	for i, statement := range statements {
+		sqls[i] = statement.SQL
+		sqlrog.Logln("info", statement.SQL)
+	}
+	if err := engine.ExecuteSQL(ctx, config, sqls); err != nil {
+		if statementErr, ok := err.(*sqlrog.StatementError); ok {
+			return statementErr.Index, errors.Wrapf(statementErr.Err, "%s:%d", scriptName, statements[statementErr.Index].Line)
+		}
+		return 0, err
+	}
+	sqlrog.Logln("info", "Done\n")
+
+	return len(statements), nil
+}
@@ -0,0 +1,108 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"github.com/stpatrickw/sqlrog/internal/sqlrog"
+)
+
+const (
+	LintFormatText        = "text"
+	LintFormatJson        = "json"
+	DefaultLintConfigFile = "lint.yml"
+)
+
+func init() {
+	var (
+		fileName  string
+		rulesFile string
+		source    string
+		format    string
+		timeout   time.Duration
+	)
+	lintCmd := &cobra.Command{
+		Use:           "lint",
+		Short:         "Lint schema",
+		Long:          "Check schema against the lint rules of the engine",
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if format != LintFormatText && format != LintFormatJson {
+				return errors.New(fmt.Sprintf("Unknown format '%s'", format))
+			}
+			if err := sqlrog.ProjectConfig.Load(fileName); err != nil {
+				return err
+			}
+			config, ok := sqlrog.ProjectConfig.Projects[source]
+			if !ok {
+				return errors.New("Source app is not found")
+			}
+			lintConfig := &sqlrog.LintConfig{}
+			if err := lintConfig.Load(rulesFile); err != nil {
+				return err
+			}
+			ctx, cancel := commandContext(timeout)
+			defer cancel()
+
+			engine := sqlrog.Engines[config.Engine]
+			schema, err := engine.LoadSchema(ctx, config, &sqlrog.YamlSchemaReader{}, nil)
+			if err != nil {
+				return err
+			}
+			issues, err := sqlrog.LintSchema(engine.GetLintRules(), schema, lintConfig)
+			if err != nil {
+				return err
+			}
+
+			errorsCount := 0
+			for _, issue := range issues {
+				if issue.Severity == sqlrog.LINT_SEVERITY_ERROR {
+					errorsCount++
+				}
+			}
+			if format == LintFormatJson {
+				if issues == nil {
+					issues = []*sqlrog.LintIssue{}
+				}
+				output, err := json.MarshalIndent(issues, "", "  ")
+				if err != nil {
+					return err
+				}
+				fmt.Println(string(output))
+			} else {
+				red := color.New(color.FgRed)
+				yellow := color.New(color.FgYellow)
+				for _, issue := range issues {
+					switch issue.Severity {
+					case sqlrog.LINT_SEVERITY_ERROR:
+						red.Println(issue.String())
+					case sqlrog.LINT_SEVERITY_WARNING:
+						yellow.Println(issue.String())
+					default:
+						fmt.Println(issue.String())
+					}
+				}
+				if len(issues) == 0 {
+					sqlrog.Logln("info", "No lint issues found")
+				}
+			}
+			if errorsCount > 0 {
+				return errors.New(fmt.Sprintf("Lint found %d error(s)", errorsCount))
+			}
+
+			return nil
+		},
+	}
+	lintCmd.Flags().StringVarP(&source, "source", "s", "", "Project to lint")
+	lintCmd.Flags().StringVarP(&rulesFile, "rules", "r", DefaultLintConfigFile, "Lint rules config file name")
+	lintCmd.Flags().StringVarP(&format, "format", "f", LintFormatText, "Output format (text/json)")
+	lintCmd.Flags().StringVarP(&fileName, "config", "c", sqlrog.DefaultConfigFileName, "Config file name")
+	lintCmd.Flags().DurationVar(&timeout, "timeout", 0, "Timeout for loading the schema (e.g. 30s, 5m)")
+
+	CliCommands = append(CliCommands, lintCmd)
+}
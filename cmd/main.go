@@ -1,6 +1,12 @@
 package main
 
 import (
+	"context"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
 	"github.com/spf13/cobra"
 	_ "github.com/stpatrickw/sqlrog/internal/firebird2.5"
 	_ "github.com/stpatrickw/sqlrog/internal/mysql5.6"
@@ -17,5 +23,31 @@ func main() {
 
 	if err := rootCmd.Execute(); err != nil {
 		sqlrog.Log("error", err.Error())
+		os.Exit(1)
+	}
+}
+
+func commandContext(timeout time.Duration) (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancelTimeout := context.CancelFunc(func() {})
+	if timeout > 0 {
+		// the timeout context is derived from the cancel one, so an interrupt cancels both
+		ctx, cancelTimeout = context.WithTimeout(ctx, timeout)
+	}
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		select {
+		case <-signals:
+			sqlrog.Logln("warn", "Interrupted, cancelling...")
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	return ctx, func() {
+		signal.Stop(signals)
+		cancelTimeout()
+		cancel()
 	}
 }
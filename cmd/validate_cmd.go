@@ -0,0 +1,58 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"github.com/stpatrickw/sqlrog/internal/sqlrog"
+)
+
+func init() {
+	var (
+		fileName string
+		project  string
+		timeout  time.Duration
+	)
+	validateCmd := &cobra.Command{
+		Use:           "validate",
+		Short:         "Validate file project",
+		Long:          "Check that the schema files of a file project are consistent",
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := sqlrog.ProjectConfig.Load(fileName); err != nil {
+				return err
+			}
+			config, ok := sqlrog.ProjectConfig.Projects[project]
+			if !ok {
+				return errors.New("Project is not found")
+			}
+			if config.AppType != sqlrog.ProjectTypeFile {
+				return errors.New("Only file projects can be validated")
+			}
+			ctx, cancel := commandContext(timeout)
+			defer cancel()
+
+			validationErrors, err := sqlrog.Engines[config.Engine].ValidateSchema(ctx, config, &sqlrog.YamlSchemaReader{})
+			if err != nil {
+				return err
+			}
+			if len(validationErrors) > 0 {
+				for _, validationError := range validationErrors {
+					sqlrog.Logln("error", validationError.Error())
+				}
+				return errors.New(fmt.Sprintf("Project %s has %d error(s)", project, len(validationErrors)))
+			}
+			sqlrog.Logln("info", fmt.Sprintf("Project %s is valid", project))
+
+			return nil
+		},
+	}
+	validateCmd.Flags().StringVarP(&project, "project", "p", "", "File project")
+	validateCmd.Flags().StringVarP(&fileName, "config", "c", sqlrog.DefaultConfigFileName, "Config file name")
+	validateCmd.Flags().DurationVar(&timeout, "timeout", 0, "Timeout for loading the project (e.g. 30s, 5m)")
+
+	CliCommands = append(CliCommands, validateCmd)
+}
@@ -0,0 +1,133 @@
+package fb
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/stpatrickw/sqlrog/internal/sqlrog"
+)
+
+const (
+	CORE_ELEMENT_BLOB_FILTER_NAME        = "blob_filter"
+	CORE_ELEMENT_BLOB_FILTER_PLURAL_NAME = "blob_filters"
+	BLOB_FILTER_DROP_PRIORITY            = -8
+)
+
+type BlobFilter struct {
+	sqlrog.BaseElementSchema `yaml:"base,omitempty"`
+	Name                     string `yaml:"name"`
+	InputType                int    `yaml:"input_type"`
+	OutputType               int    `yaml:"output_type"`
+	EntryPoint               string `yaml:"entry_point"`
+	ModuleName               string `yaml:"module_name"`
+	Comment                  string `yaml:"comment,omitempty"`
+}
+
+func (b *BlobFilter) GetName() string {
+	return b.Name
+}
+
+func (b *BlobFilter) GetTypeName() string {
+	return CORE_ELEMENT_BLOB_FILTER_NAME
+}
+
+func (b *BlobFilter) GetPluralTypeName() string {
+	return CORE_ELEMENT_BLOB_FILTER_PLURAL_NAME
+}
+
+// AlterDefinition redeclares the filter, there is no ALTER FILTER statement.
+func (b *BlobFilter) AlterDefinition(other interface{}, sep string) []string {
+	return append(b.CastType(other).DropDefinition(sep), b.CreateDefinition(sep)...)
+}
+
+func (b *BlobFilter) CreateDefinition(sep string) []string {
+	definitions := []string{fmt.Sprintf("DECLARE %s", b.Definition(sep))}
+	if comment := b.AddComment(sep); comment != "" {
+		definitions = append(definitions, comment)
+	}
+	return definitions
+}
+
+func (b *BlobFilter) DropDefinition(sep string) []string {
+	return []string{fmt.Sprintf("DROP FILTER %s%s", QuoteIdentifier(b.Name), sep)}
+}
+
+func (b *BlobFilter) Definition(sep string) string {
+	return fmt.Sprintf("FILTER %s\nINPUT_TYPE %d OUTPUT_TYPE %d\nENTRY_POINT %s MODULE_NAME %s%s",
+		QuoteIdentifier(b.Name), b.InputType, b.OutputType, StringLiteral(b.EntryPoint), StringLiteral(b.ModuleName), sep)
+}
+
+func (b *BlobFilter) AddComment(sep string) string {
+	if b.Comment != "" {
+		return fmt.Sprintf("COMMENT ON FILTER %s IS %s%s", QuoteIdentifier(b.Name), StringLiteral(b.Comment), sep)
+	}
+	return ""
+}
+
+func (b *BlobFilter) Equals(e2 interface{}) bool {
+	other := b.CastType(e2)
+
+	return b.Name == other.Name && b.InputType == other.InputType && b.OutputType == other.OutputType &&
+		b.EntryPoint == other.EntryPoint && b.ModuleName == other.ModuleName && b.Comment == other.Comment
+}
+
+func (b *BlobFilter) Diff(e2 interface{}) *sqlrog.DiffObject {
+	other := b.CastType(e2)
+
+	if !b.Equals(other) {
+		return &sqlrog.DiffObject{
+			State: sqlrog.DIFF_TYPE_UPDATE,
+			Type:  b.GetTypeName(),
+			From:  b,
+			To:    other,
+		}
+	}
+
+	return nil
+}
+
+func (b *BlobFilter) CastType(other interface{}) *BlobFilter {
+	return other.(*BlobFilter)
+}
+
+func (b *BlobFilter) FetchElementsFromDB(ctx context.Context, conn *sql.DB, filter *sqlrog.ElementFilter) ([]sqlrog.ElementSchema, error) {
+	var filters []sqlrog.ElementSchema
+
+	condition, args := FilterCondition(filter, "RDB$FUNCTION_NAME")
+	rows, err := conn.QueryContext(ctx, `
+		SELECT TRIM(RDB$FUNCTION_NAME), RDB$INPUT_SUB_TYPE, RDB$OUTPUT_SUB_TYPE,
+			TRIM(COALESCE(RDB$ENTRYPOINT, '')), TRIM(COALESCE(RDB$MODULE_NAME, '')), TRIM(COALESCE(RDB$DESCRIPTION, ''))
+		FROM RDB$FILTERS
+		WHERE COALESCE(RDB$SYSTEM_FLAG, 0) = 0`+condition+`
+		ORDER BY 1`, args...)
+	if err != nil {
+		return filters, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		blobFilter := &BlobFilter{}
+		err := rows.Scan(&blobFilter.Name, &blobFilter.InputType, &blobFilter.OutputType,
+			&blobFilter.EntryPoint, &blobFilter.ModuleName, &blobFilter.Comment)
+		if err != nil {
+			return nil, err
+		}
+		filters = append(filters, blobFilter)
+	}
+
+	return filters, nil
+}
+
+func (b *BlobFilter) DiffsOnCreate(schema sqlrog.ElementSchema) []*sqlrog.DiffObject {
+	return b.BaseElementSchema.DiffsOnCreate(schema)
+}
+
+// DiffsOnDrop goes last, after the objects using the filter are changed.
+func (b *BlobFilter) DiffsOnDrop(schema sqlrog.ElementSchema) []*sqlrog.DiffObject {
+	diffs := b.BaseElementSchema.DiffsOnDrop(schema)
+	for _, diff := range diffs {
+		diff.Priority = BLOB_FILTER_DROP_PRIORITY
+	}
+
+	return diffs
+}
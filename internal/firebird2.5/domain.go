@@ -1,6 +1,7 @@
 package fb
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
 	"github.com/stpatrickw/sqlrog/internal/sqlrog"
@@ -32,10 +33,34 @@ func (d *Domain) GetPluralTypeName() string {
 	return CORE_ELEMENT_DOMAIN_PLURAL_NAME
 }
 
+// AlterDefinition changes only what differs, NOT NULL is switched in the metadata
+// since Firebird 2.5 has no ALTER DOMAIN clause for it.
 func (d *Domain) AlterDefinition(other interface{}, sep string) []string {
-	definitions := []string{fmt.Sprintf("ALTER %s", d.CastType(other).Definition(sep))}
-	if comment := d.AddComment(sep); comment != "" {
-		definitions = append(definitions, comment)
+	target := d.CastType(other)
+	var definitions []string
+	if d.Type != target.Type {
+		definitions = append(definitions, fmt.Sprintf("ALTER DOMAIN %s TYPE %s%s", QuoteIdentifier(d.Name), d.Type, sep))
+	}
+	if d.Default != target.Default {
+		if d.Default == "" {
+			definitions = append(definitions, fmt.Sprintf("ALTER DOMAIN %s DROP DEFAULT%s", QuoteIdentifier(d.Name), sep))
+		} else {
+			definitions = append(definitions, fmt.Sprintf("ALTER DOMAIN %s SET %s%s", QuoteIdentifier(d.Name), d.Default, sep))
+		}
+	}
+	if d.Notnull != target.Notnull {
+		flag := "NULL"
+		if d.Notnull {
+			flag = "1"
+		}
+		definitions = append(definitions, fmt.Sprintf("UPDATE RDB$FIELDS SET RDB$NULL_FLAG = %s WHERE RDB$FIELD_NAME = %s%s", flag, StringLiteral(d.Name), sep))
+	}
+	if d.Comment != target.Comment {
+		if d.Comment == "" {
+			definitions = append(definitions, fmt.Sprintf("COMMENT ON DOMAIN %s IS NULL%s", QuoteIdentifier(d.Name), sep))
+		} else {
+			definitions = append(definitions, d.AddComment(sep))
+		}
 	}
 	return definitions
 }
@@ -49,11 +74,11 @@ func (d *Domain) CreateDefinition(sep string) []string {
 }
 
 func (d *Domain) DropDefinition(sep string) []string {
-	return []string{fmt.Sprintf("DROP DOMAIN %s%s", d.Name, sep)}
+	return []string{fmt.Sprintf("DROP DOMAIN %s%s", QuoteIdentifier(d.Name), sep)}
 }
 
 func (d *Domain) Definition(sep string) string {
-	SQL := fmt.Sprintf("DOMAIN %s AS %s", d.Name, d.Type)
+	SQL := fmt.Sprintf("DOMAIN %s AS %s", QuoteIdentifier(d.Name), d.Type)
 	if d.Default != "" {
 		SQL += " " + d.Default
 	}
@@ -75,7 +100,7 @@ func (d *Domain) DiffsOnDrop(schema sqlrog.ElementSchema) []*sqlrog.DiffObject {
 
 func (d *Domain) AddComment(sep string) string {
 	if d.Comment != "" {
-		return fmt.Sprintf("COMMENT ON DOMAIN %s IS '%s'%s", d.Name, d.Comment, sep)
+		return fmt.Sprintf("COMMENT ON DOMAIN %s IS %s%s", QuoteIdentifier(d.Name), StringLiteral(d.Comment), sep)
 	}
 	return ""
 }
@@ -106,10 +131,12 @@ func (d *Domain) CastType(other interface{}) *Domain {
 	return other.(*Domain)
 }
 
-func (d *Domain) FetchElementsFromDB(conn *sql.DB) ([]sqlrog.ElementSchema, error) {
+func (d *Domain) FetchElementsFromDB(ctx context.Context, conn *sql.DB, filter *sqlrog.ElementFilter) ([]sqlrog.ElementSchema, error) {
 	var domains []sqlrog.ElementSchema
 
-	rows, err := conn.Query(`
+	condition, args := FilterCondition(filter, "F.RDB$FIELD_NAME")
+
+	rows, err := conn.QueryContext(ctx, `
 		select
 		 trim(F.RDB$FIELD_NAME),
 		 trim(CASE F.RDB$FIELD_TYPE
@@ -149,8 +176,8 @@ func (d *Domain) FetchElementsFromDB(conn *sql.DB) ([]sqlrog.ElementSchema, erro
 		trim(coalesce(F.rdb$description, ''))
 		FROM RDB$FIELDS F
 		LEFT OUTER JOIN RDB$CHARACTER_SETS CH ON (CH.RDB$CHARACTER_SET_ID = F.RDB$CHARACTER_SET_ID)
-		WHERE COALESCE( F.rdb$system_flag, 0) = 0 AND NOT ( F.rdb$field_name STARTING WITH 'RDB$')
-		order by 1`)
+		WHERE COALESCE( F.rdb$system_flag, 0) = 0 AND NOT ( F.rdb$field_name STARTING WITH 'RDB$')`+condition+`
+		order by 1`, args...)
 	if err != nil {
 		return domains, err
 	}
@@ -1,6 +1,7 @@
 package fb
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
 	"github.com/stpatrickw/sqlrog/internal/sqlrog"
@@ -48,16 +49,16 @@ func (e *Exception) CreateDefinition(sep string) []string {
 }
 
 func (e *Exception) DropDefinition(sep string) []string {
-	return []string{fmt.Sprintf("DROP EXCEPTION %s%s", e.Name, sep)}
+	return []string{fmt.Sprintf("DROP EXCEPTION %s%s", QuoteIdentifier(e.Name), sep)}
 }
 
 func (e *Exception) Definition(sep string) string {
-	return fmt.Sprintf("EXCEPTION %s '%s'%s", e.Name, e.Message, sep)
+	return fmt.Sprintf("EXCEPTION %s %s%s", QuoteIdentifier(e.Name), StringLiteral(e.Message), sep)
 }
 
 func (e *Exception) AddComment(sep string) string {
 	if e.Comment != "" {
-		return fmt.Sprintf("COMMENT ON EXCEPTION %s IS '%s'%s", e.Name, e.Comment, sep)
+		return fmt.Sprintf("COMMENT ON EXCEPTION %s IS %s%s", QuoteIdentifier(e.Name), StringLiteral(e.Comment), sep)
 	}
 	return ""
 }
@@ -87,13 +88,14 @@ func (e *Exception) CastType(other interface{}) *Exception {
 	return other.(*Exception)
 }
 
-func (e *Exception) FetchElementsFromDB(conn *sql.DB) ([]sqlrog.ElementSchema, error) {
+func (e *Exception) FetchElementsFromDB(ctx context.Context, conn *sql.DB, filter *sqlrog.ElementFilter) ([]sqlrog.ElementSchema, error) {
 	var exceptions []sqlrog.ElementSchema
-	exRows, err := conn.Query(`
+	condition, args := FilterCondition(filter, "ex.rdb$exception_name")
+	exRows, err := conn.QueryContext(ctx, `
 		select trim(ex.rdb$exception_name), ex.rdb$exception_number, trim(coalesce(ex.rdb$message, '')), trim(coalesce(ex.rdb$description, ''))
 		from rdb$exceptions ex
-		where rdb$system_flag = 0
-		order by 1`)
+		where rdb$system_flag = 0`+condition+`
+		order by 1`, args...)
 	if err != nil {
 		return exceptions, err
 	}
@@ -0,0 +1,253 @@
+package fb
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/stpatrickw/sqlrog/internal/sqlrog"
+)
+
+const (
+	CORE_ELEMENT_EXTERNAL_FUNCTION_NAME        = "external_function"
+	CORE_ELEMENT_EXTERNAL_FUNCTION_PLURAL_NAME = "external_functions"
+	EXTERNAL_FUNCTION_PRIORITY                 = 11
+	EXTERNAL_FUNCTION_DROP_PRIORITY            = -8
+)
+
+var externalFunctionMechanisms = map[int]string{
+	0: "BY VALUE",
+	2: "BY DESCRIPTOR",
+	4: "BY SCALAR_ARRAY",
+	5: "NULL",
+}
+
+type ExternalFunction struct {
+	sqlrog.BaseElementSchema `yaml:"base,omitempty"`
+	Name                     string                      `yaml:"name"`
+	Arguments                []*ExternalFunctionArgument `yaml:"arguments,omitempty"`
+	Returns                  *ExternalFunctionArgument   `yaml:"returns,omitempty"`
+	ReturnsParameter         int                         `yaml:"returns_parameter,omitempty"`
+	EntryPoint               string                      `yaml:"entry_point"`
+	ModuleName               string                      `yaml:"module_name"`
+	Comment                  string                      `yaml:"comment,omitempty"`
+}
+
+type ExternalFunctionArgument struct {
+	Type      string `yaml:"type"`
+	Mechanism string `yaml:"mechanism,omitempty"`
+	FreeIt    bool   `yaml:"free_it,omitempty"`
+}
+
+func (a *ExternalFunctionArgument) Definition() string {
+	definition := a.Type
+	if a.Mechanism != "" {
+		definition += " " + a.Mechanism
+	}
+	if a.FreeIt {
+		definition += " FREE_IT"
+	}
+
+	return definition
+}
+
+func (f *ExternalFunction) GetName() string {
+	return f.Name
+}
+
+func (f *ExternalFunction) GetTypeName() string {
+	return CORE_ELEMENT_EXTERNAL_FUNCTION_NAME
+}
+
+func (f *ExternalFunction) GetPluralTypeName() string {
+	return CORE_ELEMENT_EXTERNAL_FUNCTION_PLURAL_NAME
+}
+
+// GetPriority puts external functions before tables, computed columns, checks and defaults could call them.
+func (f *ExternalFunction) GetPriority() int {
+	return EXTERNAL_FUNCTION_PRIORITY
+}
+
+// AlterDefinition redeclares the function, Firebird 2.5 has no ALTER EXTERNAL FUNCTION.
+func (f *ExternalFunction) AlterDefinition(other interface{}, sep string) []string {
+	return append(f.CastType(other).DropDefinition(sep), f.CreateDefinition(sep)...)
+}
+
+func (f *ExternalFunction) CreateDefinition(sep string) []string {
+	definitions := []string{fmt.Sprintf("DECLARE %s", f.Definition(sep))}
+	if comment := f.AddComment(sep); comment != "" {
+		definitions = append(definitions, comment)
+	}
+	return definitions
+}
+
+func (f *ExternalFunction) DropDefinition(sep string) []string {
+	return []string{fmt.Sprintf("DROP EXTERNAL FUNCTION %s%s", QuoteIdentifier(f.Name), sep)}
+}
+
+func (f *ExternalFunction) Definition(sep string) string {
+	SQL := fmt.Sprintf("EXTERNAL FUNCTION %s", QuoteIdentifier(f.Name))
+	var arguments []string
+	for _, argument := range f.Arguments {
+		arguments = append(arguments, argument.Definition())
+	}
+	if len(arguments) > 0 {
+		SQL += "\n  " + strings.Join(arguments, ",\n  ")
+	}
+	if f.ReturnsParameter > 0 {
+		SQL += fmt.Sprintf("\nRETURNS PARAMETER %d", f.ReturnsParameter)
+	} else if f.Returns != nil {
+		SQL += "\nRETURNS " + f.Returns.Definition()
+	}
+	SQL += fmt.Sprintf("\nENTRY_POINT %s MODULE_NAME %s", StringLiteral(f.EntryPoint), StringLiteral(f.ModuleName))
+
+	return SQL + sep
+}
+
+func (f *ExternalFunction) AddComment(sep string) string {
+	if f.Comment != "" {
+		return fmt.Sprintf("COMMENT ON EXTERNAL FUNCTION %s IS %s%s", QuoteIdentifier(f.Name), StringLiteral(f.Comment), sep)
+	}
+	return ""
+}
+
+func (f *ExternalFunction) Equals(e2 interface{}) bool {
+	other := f.CastType(e2)
+
+	return f.Definition("") == other.Definition("") && f.Comment == other.Comment
+}
+
+func (f *ExternalFunction) Diff(e2 interface{}) *sqlrog.DiffObject {
+	other := f.CastType(e2)
+
+	if !f.Equals(other) {
+		return &sqlrog.DiffObject{
+			State:    sqlrog.DIFF_TYPE_UPDATE,
+			Type:     f.GetTypeName(),
+			From:     f,
+			To:       other,
+			Priority: f.GetPriority(),
+		}
+	}
+
+	return nil
+}
+
+func (f *ExternalFunction) CastType(other interface{}) *ExternalFunction {
+	return other.(*ExternalFunction)
+}
+
+func (f *ExternalFunction) FetchElementsFromDB(ctx context.Context, conn *sql.DB, filter *sqlrog.ElementFilter) ([]sqlrog.ElementSchema, error) {
+	var functions []sqlrog.ElementSchema
+	functionsByName := make(map[string]*ExternalFunction)
+
+	condition, args := FilterCondition(filter, "F.RDB$FUNCTION_NAME")
+	rows, err := conn.QueryContext(ctx, `
+		SELECT TRIM(F.RDB$FUNCTION_NAME), TRIM(COALESCE(F.RDB$ENTRYPOINT, '')), TRIM(COALESCE(F.RDB$MODULE_NAME, '')),
+			COALESCE(F.RDB$RETURN_ARGUMENT, 0), TRIM(COALESCE(F.RDB$DESCRIPTION, ''))
+		FROM RDB$FUNCTIONS F
+		WHERE COALESCE(F.RDB$SYSTEM_FLAG, 0) = 0`+condition+`
+		ORDER BY 1`, args...)
+	if err != nil {
+		return functions, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		function := &ExternalFunction{}
+		err := rows.Scan(&function.Name, &function.EntryPoint, &function.ModuleName, &function.ReturnsParameter, &function.Comment)
+		if err != nil {
+			return nil, err
+		}
+		functions = append(functions, function)
+		functionsByName[function.Name] = function
+	}
+	rows.Close()
+
+	argumentRows, err := conn.QueryContext(ctx, `
+		SELECT TRIM(FA.RDB$FUNCTION_NAME), FA.RDB$ARGUMENT_POSITION, COALESCE(FA.RDB$MECHANISM, 1),
+			TRIM(CASE FA.RDB$FIELD_TYPE
+				WHEN 7 THEN
+				  CASE FA.RDB$FIELD_SUB_TYPE
+					WHEN 1 THEN 'NUMERIC(' || FA.RDB$FIELD_PRECISION || ', ' || (-FA.RDB$FIELD_SCALE) || ')'
+					WHEN 2 THEN 'DECIMAL(' || FA.RDB$FIELD_PRECISION || ', ' || (-FA.RDB$FIELD_SCALE) || ')'
+					ELSE 'SMALLINT'
+				  END
+				WHEN 8 THEN
+				  CASE FA.RDB$FIELD_SUB_TYPE
+					WHEN 1 THEN 'NUMERIC(' || FA.RDB$FIELD_PRECISION || ', ' || (-FA.RDB$FIELD_SCALE) || ')'
+					WHEN 2 THEN 'DECIMAL(' || FA.RDB$FIELD_PRECISION || ', ' || (-FA.RDB$FIELD_SCALE) || ')'
+					ELSE 'INTEGER'
+				  END
+				WHEN 10 THEN 'FLOAT'
+				WHEN 12 THEN 'DATE'
+				WHEN 13 THEN 'TIME'
+				WHEN 14 THEN 'CHAR(' || COALESCE(FA.RDB$CHARACTER_LENGTH, FA.RDB$FIELD_LENGTH) || ')'
+				WHEN 16 THEN
+				  CASE FA.RDB$FIELD_SUB_TYPE
+					WHEN 1 THEN 'NUMERIC(' || FA.RDB$FIELD_PRECISION || ', ' || (-FA.RDB$FIELD_SCALE) || ')'
+					WHEN 2 THEN 'DECIMAL(' || FA.RDB$FIELD_PRECISION || ', ' || (-FA.RDB$FIELD_SCALE) || ')'
+					ELSE 'BIGINT'
+				  END
+				WHEN 27 THEN 'DOUBLE PRECISION'
+				WHEN 35 THEN 'TIMESTAMP'
+				WHEN 37 THEN 'VARCHAR(' || COALESCE(FA.RDB$CHARACTER_LENGTH, FA.RDB$FIELD_LENGTH) || ')'
+				WHEN 40 THEN 'CSTRING(' || COALESCE(FA.RDB$CHARACTER_LENGTH, FA.RDB$FIELD_LENGTH) || ')'
+				WHEN 261 THEN 'BLOB'
+				ELSE 'RDB$FIELD_TYPE: ' || FA.RDB$FIELD_TYPE || '?'
+			END) ||
+			CASE WHEN FA.RDB$FIELD_TYPE IN (14, 37, 40) AND CH.RDB$CHARACTER_SET_NAME IS NOT NULL AND TRIM(CH.RDB$CHARACTER_SET_NAME) <> 'NONE'
+				THEN ' CHARACTER SET ' || TRIM(CH.RDB$CHARACTER_SET_NAME) ELSE '' END
+		FROM RDB$FUNCTION_ARGUMENTS FA
+		JOIN RDB$FUNCTIONS F ON (F.RDB$FUNCTION_NAME = FA.RDB$FUNCTION_NAME)
+		LEFT OUTER JOIN RDB$CHARACTER_SETS CH ON (CH.RDB$CHARACTER_SET_ID = FA.RDB$CHARACTER_SET_ID)
+		WHERE COALESCE(F.RDB$SYSTEM_FLAG, 0) = 0`+condition+`
+		ORDER BY 1, 2`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer argumentRows.Close()
+	for argumentRows.Next() {
+		var functionName string
+		var position, mechanism int
+		argument := &ExternalFunctionArgument{}
+		err := argumentRows.Scan(&functionName, &position, &mechanism, &argument.Type)
+		if err != nil {
+			return nil, err
+		}
+		function, ok := functionsByName[functionName]
+		if !ok {
+			continue
+		}
+		// negative mechanism marks the returned value to be freed by the engine
+		if mechanism < 0 {
+			argument.FreeIt = true
+			mechanism = -mechanism
+		}
+		argument.Mechanism = externalFunctionMechanisms[mechanism]
+		if position == function.ReturnsParameter {
+			if function.ReturnsParameter == 0 {
+				function.Returns = argument
+				continue
+			}
+			argument.FreeIt = false
+		}
+		function.Arguments = append(function.Arguments, argument)
+	}
+
+	return functions, nil
+}
+
+func (f *ExternalFunction) DiffsOnCreate(schema sqlrog.ElementSchema) []*sqlrog.DiffObject {
+	return f.BaseElementSchema.DiffsOnCreate(schema)
+}
+
+// DiffsOnDrop declares the function away after procedures, views, triggers and tables calling it are changed.
+func (f *ExternalFunction) DiffsOnDrop(schema sqlrog.ElementSchema) []*sqlrog.DiffObject {
+	diffs := f.BaseElementSchema.DiffsOnDrop(schema)
+	for _, diff := range diffs {
+		diff.Priority = EXTERNAL_FUNCTION_DROP_PRIORITY
+	}
+
+	return diffs
+}
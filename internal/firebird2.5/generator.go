@@ -1,9 +1,12 @@
 package fb
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
 	"github.com/stpatrickw/sqlrog/internal/sqlrog"
+	"regexp"
+	"strings"
 )
 
 const (
@@ -11,10 +14,16 @@ const (
 	CORE_ELEMENT_GENERATOR_PLURAL_NAME = "generators"
 )
 
+var generatorOwnerRegexp = regexp.MustCompile(`(?i)NEW\.(\w+|"[^"]+")\s*=\s*(?:GEN_ID\s*\(\s*(\w+|"[^"]+")\s*,|NEXT\s+VALUE\s+FOR\s+(\w+|"[^"]+"))`)
+
 type Generator struct {
 	sqlrog.BaseElementSchema `yaml:"base,omitempty"`
 	Name                     string `yaml:"name"`
 	Comment                  string `yaml:"comment"`
+	Value                    int64  `yaml:"value,omitempty"`
+	Owner                    string `yaml:"owner,omitempty"`
+	Policy                   string `yaml:"-"`
+	MaxValue                 int64  `yaml:"-"`
 }
 
 func (g *Generator) GetName() string {
@@ -30,9 +39,17 @@ func (g *Generator) GetPluralTypeName() string {
 }
 
 func (g *Generator) AlterDefinition(other interface{}, sep string) []string {
-	definitions := []string{fmt.Sprintf("ALTER %s", g.CastType(other).Definition(sep))}
-	if comment := g.AddComment(sep); comment != "" {
-		definitions = append(definitions, comment)
+	target := g.CastType(other)
+	var definitions []string
+	if g.Comment != target.Comment {
+		if g.Comment == "" {
+			definitions = append(definitions, fmt.Sprintf("COMMENT ON SEQUENCE %s IS NULL%s", QuoteIdentifier(g.Name), sep))
+		} else {
+			definitions = append(definitions, g.AddComment(sep))
+		}
+	}
+	if value := g.ValueDefinition(target, sep); value != "" {
+		definitions = append(definitions, value)
 	}
 	return definitions
 }
@@ -42,25 +59,52 @@ func (g *Generator) CreateDefinition(sep string) []string {
 	if comment := g.AddComment(sep); comment != "" {
 		definitions = append(definitions, comment)
 	}
+	if value := g.ValueDefinition(nil, sep); value != "" {
+		definitions = append(definitions, value)
+	}
 	return definitions
 }
 
 func (g *Generator) DropDefinition(sep string) []string {
-	g.Comment = ""
 	return []string{fmt.Sprintf("DROP %s", g.Definition(sep))}
 }
 
 func (g *Generator) Definition(sep string) string {
-	SQL := fmt.Sprintf("SEQUENCE %s%s\n", g.Name, sep)
-	if g.Comment != "" {
-		SQL += fmt.Sprintf("COMMENT ON SEQUENCE %s IS '%s'\n", g.Name, g.Comment)
+	return fmt.Sprintf("SEQUENCE %s%s\n", QuoteIdentifier(g.Name), sep)
+}
+
+// ValueDefinition moves the value of the target generator according to the policy,
+// target is nil for a new generator. Max policy looks up the owning column when it's applied.
+func (g *Generator) ValueDefinition(target *Generator, sep string) string {
+	switch g.Policy {
+	case sqlrog.GENERATOR_VALUES_EXACT, sqlrog.GENERATOR_VALUES_RAISE:
+		if target == nil && g.Value == 0 {
+			return ""
+		}
+		if target != nil && (g.Value == target.Value || g.Policy == sqlrog.GENERATOR_VALUES_RAISE && g.Value < target.Value) {
+			return ""
+		}
+		return fmt.Sprintf("ALTER SEQUENCE %s RESTART WITH %d%s", QuoteIdentifier(g.Name), g.Value, sep)
+	case sqlrog.GENERATOR_VALUES_MAX:
+		owner := strings.SplitN(g.Owner, ".", 2)
+		if len(owner) != 2 || target != nil && target.MaxValue <= target.Value {
+			return ""
+		}
+		return fmt.Sprintf(`EXECUTE BLOCK AS
+DECLARE VARIABLE MAX_VALUE BIGINT;
+BEGIN
+  SELECT COALESCE(MAX(%s), 0) FROM %s INTO :MAX_VALUE;
+  IF (MAX_VALUE > GEN_ID(%s, 0)) THEN
+    MAX_VALUE = GEN_ID(%s, MAX_VALUE - GEN_ID(%s, 0));
+END%s`, QuoteIdentifier(owner[1]), QuoteIdentifier(owner[0]), QuoteIdentifier(g.Name), QuoteIdentifier(g.Name), QuoteIdentifier(g.Name), sep)
 	}
-	return SQL
+
+	return ""
 }
 
 func (g *Generator) AddComment(sep string) string {
 	if g.Comment != "" {
-		return fmt.Sprintf("COMMENT ON SEQUENCE %s IS '%s'%s", g.Name, g.Comment, sep)
+		return fmt.Sprintf("COMMENT ON SEQUENCE %s IS %s%s", QuoteIdentifier(g.Name), StringLiteral(g.Comment), sep)
 	}
 	return ""
 }
@@ -68,7 +112,7 @@ func (g *Generator) AddComment(sep string) string {
 func (g *Generator) Equals(g2 interface{}) bool {
 	other := g.CastType(g2)
 
-	return g.Name == other.Name && g.Comment == other.Comment
+	return g.Name == other.Name && g.Comment == other.Comment && g.ValueDefinition(other, "") == ""
 }
 
 func (g *Generator) Diff(e2 interface{}) *sqlrog.DiffObject {
@@ -90,14 +134,16 @@ func (g *Generator) CastType(other interface{}) *Generator {
 	return other.(*Generator)
 }
 
-func (g *Generator) FetchElementsFromDB(conn *sql.DB) ([]sqlrog.ElementSchema, error) {
+func (g *Generator) FetchElementsFromDB(ctx context.Context, conn *sql.DB, filter *sqlrog.ElementFilter) ([]sqlrog.ElementSchema, error) {
 	var generators []sqlrog.ElementSchema
 
-	rows, err := conn.Query(`
+	condition, args := FilterCondition(filter, "rdb$generator_name")
+
+	rows, err := conn.QueryContext(ctx, `
 		select trim(rdb$generator_name), trim(coalesce(rdb$description, ''))
 		from rdb$generators
-		where rdb$system_flag = 0
-		order by 1`)
+		where rdb$system_flag = 0`+condition+`
+		order by 1`, args...)
 	if err != nil {
 		return generators, err
 	}
@@ -114,6 +160,63 @@ func (g *Generator) FetchElementsFromDB(conn *sql.DB) ([]sqlrog.ElementSchema, e
 	return generators, nil
 }
 
+// FetchValuesFromDB reads current values of generators, their owning columns from
+// table triggers and, for max policy, maximal values of owning columns.
+func (g *Generator) FetchValuesFromDB(ctx context.Context, conn *sql.DB, generators map[string]sqlrog.ElementSchema, policy string) error {
+	owners := make(map[string]string)
+	rows, err := conn.QueryContext(ctx, `
+		select trim(rdb$relation_name), rdb$trigger_source
+		from rdb$triggers
+		where rdb$relation_name is not null and rdb$system_flag = 0 and rdb$trigger_source is not null`)
+	if err != nil {
+		return err
+	}
+	for rows.Next() {
+		var tableName, source string
+		if err := rows.Scan(&tableName, &source); err != nil {
+			rows.Close()
+			return err
+		}
+		for _, match := range generatorOwnerRegexp.FindAllStringSubmatch(source, -1) {
+			name := UnquoteIdentifier(match[2] + match[3])
+			if _, ok := owners[name]; !ok {
+				owners[name] = tableName + "." + UnquoteIdentifier(match[1])
+			}
+		}
+	}
+	rows.Close()
+	for _, element := range generators {
+		generator := element.(*Generator)
+		if generator.Owner == "" {
+			generator.Owner = owners[generator.Name]
+		}
+		err := conn.QueryRowContext(ctx, fmt.Sprintf("select gen_id(%s, 0) from rdb$database", QuoteIdentifier(generator.Name))).Scan(&generator.Value)
+		if err != nil {
+			return err
+		}
+		owner := strings.SplitN(generator.Owner, ".", 2)
+		if policy != sqlrog.GENERATOR_VALUES_MAX || len(owner) != 2 {
+			continue
+		}
+		err = conn.QueryRowContext(ctx, fmt.Sprintf("select coalesce(max(%s), 0) from %s", QuoteIdentifier(owner[1]), QuoteIdentifier(owner[0]))).Scan(&generator.MaxValue)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (fbs *FbSchema) SetGeneratorValuesPolicy(policy string) {
+	for _, element := range fbs.CoreElements[CORE_ELEMENT_GENERATOR_NAME] {
+		generator := element.(*Generator)
+		generator.Policy = policy
+		if policy == "" {
+			generator.Value = 0
+		}
+	}
+}
+
 func (g *Generator) DiffsOnCreate(schema sqlrog.ElementSchema) []*sqlrog.DiffObject {
 	return g.BaseElementSchema.DiffsOnCreate(schema)
 }
@@ -0,0 +1,206 @@
+package fb
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/stpatrickw/sqlrog/internal/sqlrog"
+)
+
+const (
+	CORE_ELEMENT_GRANT_NAME        = "grant"
+	CORE_ELEMENT_GRANT_PLURAL_NAME = "grants"
+	GRANT_PRIORITY                 = -5
+	GRANT_OBJECT_TABLE             = "TABLE"
+	GRANT_OBJECT_PROCEDURE         = "PROCEDURE"
+	GRANT_OBJECT_ROLE              = "ROLE"
+	GRANTEE_USER                   = "USER"
+)
+
+var (
+	privilegeNames = map[string]string{
+		"S": "SELECT",
+		"I": "INSERT",
+		"U": "UPDATE",
+		"D": "DELETE",
+		"R": "REFERENCES",
+		"X": "EXECUTE",
+		"M": "MEMBER",
+	}
+	granteeTypes = map[int]string{
+		1:  "VIEW",
+		2:  "TRIGGER",
+		5:  "PROCEDURE",
+		8:  GRANTEE_USER,
+		13: "ROLE",
+	}
+)
+
+type Grant struct {
+	sqlrog.BaseElementSchema `yaml:"base,omitempty"`
+	Grantee                  string              `yaml:"grantee"`
+	GranteeType              string              `yaml:"grantee_type"`
+	Privileges               []*sqlrog.Privilege `yaml:"privileges"`
+}
+
+func (g *Grant) GetName() string {
+	return g.Grantee
+}
+
+func (g *Grant) GetTypeName() string {
+	return CORE_ELEMENT_GRANT_NAME
+}
+
+func (g *Grant) GetPluralTypeName() string {
+	return CORE_ELEMENT_GRANT_PLURAL_NAME
+}
+
+// GetPriority puts grants after all objects are created.
+func (g *Grant) GetPriority() int {
+	return GRANT_PRIORITY
+}
+
+func (g *Grant) AlterDefinition(other interface{}, sep string) []string {
+	var definitions []string
+	added, removed := sqlrog.PrivilegesDiff(g.Privileges, g.CastType(other).Privileges)
+	for _, privilege := range removed {
+		definitions = append(definitions, g.RevokeDefinition(privilege, sep))
+	}
+	for _, privilege := range added {
+		definitions = append(definitions, g.GrantDefinition(privilege, sep))
+	}
+
+	return definitions
+}
+
+func (g *Grant) CreateDefinition(sep string) []string {
+	return g.AlterDefinition(&Grant{Grantee: g.Grantee, GranteeType: g.GranteeType}, sep)
+}
+
+func (g *Grant) DropDefinition(sep string) []string {
+	return (&Grant{Grantee: g.Grantee, GranteeType: g.GranteeType}).AlterDefinition(g, sep)
+}
+
+func (g *Grant) GrantDefinition(privilege *sqlrog.Privilege, sep string) string {
+	definition := fmt.Sprintf("GRANT %s TO %s", g.PrivilegeDefinition(privilege), g.GranteeDefinition())
+	if privilege.GrantOption && privilege.ObjectType == GRANT_OBJECT_ROLE {
+		definition += " WITH ADMIN OPTION"
+	} else if privilege.GrantOption {
+		definition += " WITH GRANT OPTION"
+	}
+
+	return definition + sep
+}
+
+func (g *Grant) RevokeDefinition(privilege *sqlrog.Privilege, sep string) string {
+	return fmt.Sprintf("REVOKE %s FROM %s%s", g.PrivilegeDefinition(privilege), g.GranteeDefinition(), sep)
+}
+
+func (g *Grant) PrivilegeDefinition(privilege *sqlrog.Privilege) string {
+	switch privilege.ObjectType {
+	case GRANT_OBJECT_ROLE:
+		return QuoteIdentifier(privilege.Object)
+	case GRANT_OBJECT_PROCEDURE:
+		return fmt.Sprintf("%s ON PROCEDURE %s", privilege.Privilege, QuoteIdentifier(privilege.Object))
+	}
+	if privilege.Column != "" {
+		return fmt.Sprintf("%s (%s) ON %s", privilege.Privilege, QuoteIdentifier(privilege.Column), QuoteIdentifier(privilege.Object))
+	}
+
+	return fmt.Sprintf("%s ON %s", privilege.Privilege, QuoteIdentifier(privilege.Object))
+}
+
+func (g *Grant) GranteeDefinition() string {
+	if g.GranteeType == "" || g.GranteeType == GRANTEE_USER {
+		return QuoteIdentifier(g.Grantee)
+	}
+
+	return fmt.Sprintf("%s %s", g.GranteeType, QuoteIdentifier(g.Grantee))
+}
+
+func (g *Grant) Equals(e2 interface{}) bool {
+	other := g.CastType(e2)
+
+	return g.Grantee == other.Grantee && g.GranteeType == other.GranteeType &&
+		sqlrog.PrivilegesEqual(g.Privileges, other.Privileges)
+}
+
+func (g *Grant) Diff(e2 interface{}) *sqlrog.DiffObject {
+	other := g.CastType(e2)
+
+	if !g.Equals(other) {
+		return &sqlrog.DiffObject{
+			State:    sqlrog.DIFF_TYPE_UPDATE,
+			Type:     g.GetTypeName(),
+			From:     g,
+			To:       other,
+			Priority: GRANT_PRIORITY,
+		}
+	}
+
+	return nil
+}
+
+func (g *Grant) CastType(other interface{}) *Grant {
+	return other.(*Grant)
+}
+
+func (g *Grant) FetchElementsFromDB(ctx context.Context, conn *sql.DB, filter *sqlrog.ElementFilter) ([]sqlrog.ElementSchema, error) {
+	grantsMap := make(map[string]*Grant)
+
+	condition, args := FilterCondition(filter, "p.rdb$user")
+
+	rows, err := conn.QueryContext(ctx, `
+		SELECT trim(p.rdb$user), p.rdb$user_type, trim(p.rdb$privilege), coalesce(p.rdb$grant_option, 0),
+			trim(p.rdb$relation_name), trim(coalesce(p.rdb$field_name, '')), p.rdb$object_type
+		FROM rdb$user_privileges p
+		WHERE p.rdb$relation_name NOT STARTING WITH 'RDB$' AND p.rdb$relation_name NOT STARTING WITH 'MON$'
+			AND NOT EXISTS (SELECT 1 FROM rdb$relations r WHERE r.rdb$relation_name = p.rdb$relation_name AND r.rdb$owner_name = p.rdb$user)
+			AND NOT EXISTS (SELECT 1 FROM rdb$procedures pr WHERE pr.rdb$procedure_name = p.rdb$relation_name AND pr.rdb$owner_name = p.rdb$user)
+			AND NOT EXISTS (SELECT 1 FROM rdb$roles ro WHERE ro.rdb$role_name = p.rdb$relation_name AND ro.rdb$owner_name = p.rdb$user)`+condition+`
+		ORDER BY 1, 5, 6, 3`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var (
+			name        string
+			granteeType int
+			code        string
+			grantOption int
+			objectType  int
+		)
+		privilege := &sqlrog.Privilege{}
+		err := rows.Scan(&name, &granteeType, &code, &grantOption, &privilege.Object, &privilege.Column, &objectType)
+		if err != nil {
+			return nil, err
+		}
+		privilege.Privilege = privilegeNames[code]
+		privilege.GrantOption = grantOption > 0
+		switch objectType {
+		case 5:
+			privilege.ObjectType = GRANT_OBJECT_PROCEDURE
+		case 13:
+			privilege.ObjectType = GRANT_OBJECT_ROLE
+		default:
+			privilege.ObjectType = GRANT_OBJECT_TABLE
+		}
+		if _, ok := grantsMap[name]; !ok {
+			grantsMap[name] = &Grant{Grantee: name, GranteeType: granteeTypes[granteeType]}
+		}
+		grantsMap[name].Privileges = append(grantsMap[name].Privileges, privilege)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	var grants []sqlrog.ElementSchema
+	for _, grant := range grantsMap {
+		sqlrog.SortPrivileges(grant.Privileges)
+		grants = append(grants, grant)
+	}
+
+	return grants, nil
+}
@@ -0,0 +1,46 @@
+package fb
+
+import (
+	"strings"
+
+	"github.com/stpatrickw/sqlrog/internal/sqlrog"
+)
+
+// Names of dialect 3 are case sensitive when quoted, so only upper case names are left as they are.
+var identifierQuoter = sqlrog.NewIdentifierQuoter(`"`, `^[A-Z][A-Z0-9_$]*$`, strings.Fields(`
+	ADD ADMIN ALL ALTER AND ANY AS AT AVG BEGIN BETWEEN BIGINT BIT_LENGTH BLOB BOTH BY CASE CAST CHAR CHAR_LENGTH
+	CHARACTER CHARACTER_LENGTH CHECK CLOSE COLLATE COLUMN COMMIT CONNECT CONSTRAINT COUNT CREATE CROSS CURRENT
+	CURRENT_CONNECTION CURRENT_DATE CURRENT_ROLE CURRENT_TIME CURRENT_TIMESTAMP CURRENT_TRANSACTION CURRENT_USER
+	CURSOR DATE DAY DEC DECIMAL DECLARE DEFAULT DELETE DELETING DISCONNECT DISTINCT DOUBLE DROP ELSE END ESCAPE
+	EXECUTE EXISTS EXTERNAL EXTRACT FETCH FILTER FLOAT FOR FOREIGN FROM FULL FUNCTION GDSCODE GLOBAL GRANT GROUP
+	HAVING HOUR IN INDEX INNER INSENSITIVE INSERT INSERTING INT INTEGER INTO IS JOIN LEADING LEFT LIKE LONG LOWER
+	MAX MAXIMUM_SEGMENT MERGE MIN MINUTE MONTH NATIONAL NATURAL NCHAR NO NOT NULL NUMERIC OCTET_LENGTH OF ON ONLY
+	OPEN OR ORDER OUTER PARAMETER PLAN POSITION POST_EVENT PRECISION PRIMARY PROCEDURE RDB$DB_KEY REAL
+	RECORD_VERSION RECREATE RECURSIVE REFERENCES RELEASE RETURNING_VALUES RETURNS REVOKE RIGHT ROLLBACK ROW_COUNT
+	ROWS SAVEPOINT SECOND SELECT SENSITIVE SET SIMILAR SMALLINT SOME SQLCODE SQLSTATE START SUM TABLE THEN TIME
+	TIMESTAMP TO TRAILING TRIGGER TRIM UNION UNIQUE UPDATE UPDATING UPPER USER USING VALUE VALUES VARCHAR VARIABLE
+	VARYING VIEW WHEN WHERE WHILE WITH YEAR`))
+
+// QuoteIdentifier puts reserved words and names which are not plain upper case identifiers in double quotes.
+func QuoteIdentifier(name string) string {
+	return identifierQuoter.QuoteIdentifier(name)
+}
+
+// UnquoteIdentifier turns a name used in SQL into the name stored in the catalog.
+func UnquoteIdentifier(name string) string {
+	if len(name) > 1 && strings.HasPrefix(name, `"`) && strings.HasSuffix(name, `"`) {
+		return strings.Replace(name[1:len(name)-1], `""`, `"`, -1)
+	}
+
+	return strings.ToUpper(name)
+}
+
+func QuoteIdentifiers(names []string) []string {
+	return identifierQuoter.QuoteIdentifiers(names)
+}
+
+// templateFuncs are used by DDL templates to quote names.
+var templateFuncs = map[string]interface{}{
+	"quote":   QuoteIdentifier,
+	"literal": StringLiteral,
+}
@@ -1,6 +1,7 @@
 package fb
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
 	"github.com/stpatrickw/sqlrog/internal/sqlrog"
@@ -13,6 +14,7 @@ const (
 	FOREIGN_KEY          = "FOREIGN KEY"
 	UNIQUE               = "UNIQUE"
 	INDEX                = "INDEX"
+	CHECK                = "CHECK"
 	PRIMARY_KEY_PRIORITY = 9
 	FOREIGN_KEY_PRIORITY = 7
 	INDEX_PRIORITY       = 8
@@ -45,32 +47,72 @@ func (i *Index) GetTypeName() string {
 	return "index"
 }
 
+// HookParentKey lets hooks of the table surround changes of its indexes.
+func (i *Index) HookParentKey() string {
+	return CORE_ELEMENT_TABLE_PLURAL_NAME + "/" + i.TableName
+}
+
+// AlterDefinition recreates the index only when its structure changes,
+// activity and comment are changed in place.
 func (i *Index) AlterDefinition(other interface{}, sep string) []string {
-	i2 := i.CastType(other)
-	definitions := i.DropDefinition(sep)
-	definitions = append(definitions, i2.CreateDefinition(sep)...)
+	target := i.CastType(other)
+	if !i.StructureEquals(target) {
+		definitions := target.DropDefinition(sep)
+		return append(definitions, i.CreateDefinition(sep)...)
+	}
+	var definitions []string
+	if i.HasActivity() && i.Active != target.Active {
+		definitions = append(definitions, i.ActivityDefinition(sep))
+	}
+	if i.Comment != target.Comment {
+		definitions = append(definitions, i.CommentDefinition(sep))
+	}
 
 	return definitions
 }
 
 func (i *Index) CreateDefinition(sep string) []string {
 	definitions := []string{i.Definition(sep)}
-	if !i.Active {
+	if i.HasActivity() && !i.Active {
 		definitions = append(definitions, i.ActivityDefinition(sep))
 	}
+	if i.Comment != "" {
+		definitions = append(definitions, i.CommentDefinition(sep))
+	}
 	return definitions
 }
 
+func (i *Index) CommentDefinition(sep string) string {
+	if i.Comment == "" {
+		return fmt.Sprintf("COMMENT ON INDEX %s IS NULL%s", QuoteIdentifier(i.Name), sep)
+	}
+
+	return fmt.Sprintf("COMMENT ON INDEX %s IS %s%s", QuoteIdentifier(i.Name), StringLiteral(i.Comment), sep)
+}
+
+func (i *Index) StatisticsDefinition(sep string) string {
+	return fmt.Sprintf("SET STATISTICS INDEX %s%s", QuoteIdentifier(i.Name), sep)
+}
+
 func (i *Index) DropDefinition(sep string) []string {
-	return []string{fmt.Sprintf("DROP INDEX %s%s", i.Name, sep)}
+	if i.Type == INDEX {
+		return []string{fmt.Sprintf("DROP INDEX %s%s", QuoteIdentifier(i.Name), sep)}
+	}
+
+	return []string{fmt.Sprintf("ALTER TABLE %s DROP CONSTRAINT %s%s", QuoteIdentifier(i.TableName), QuoteIdentifier(i.Name), sep)}
+}
+
+// HasActivity tells if the index could be deactivated, indexes enforcing constraints are always active.
+func (i *Index) HasActivity() bool {
+	return i.Type == INDEX
 }
 
 func (i *Index) ActivityDefinition(sep string) string {
 	if i.Active {
-		fmt.Sprintf("ALTER INDEX %s ACTIVE%s", i.Name, sep)
+		return fmt.Sprintf("ALTER INDEX %s ACTIVE%s", QuoteIdentifier(i.Name), sep)
 	}
 
-	return fmt.Sprintf("ALTER INDEX %s INACTIVE%s", i.Name, sep)
+	return fmt.Sprintf("ALTER INDEX %s INACTIVE%s", QuoteIdentifier(i.Name), sep)
 }
 
 func (i *Index) Definition(sep string) string {
@@ -79,22 +121,24 @@ func (i *Index) Definition(sep string) string {
 
 	switch i.Type {
 	case PRIMARY_KEY, UNIQUE:
-		definition = fmt.Sprintf("ALTER TABLE %s ADD CONSTRAINT %s %s (%s)", i.TableName, i.Name, i.Type, OrderedIndexFields(i.Fields))
+		definition = fmt.Sprintf("ALTER TABLE %s ADD CONSTRAINT %s %s (%s)", QuoteIdentifier(i.TableName), QuoteIdentifier(i.Name), i.Type, QuotedIndexFields(i.Fields))
 	case FOREIGN_KEY:
 		definition = fmt.Sprintf("ALTER TABLE %s ADD CONSTRAINT %s %s (%s) REFERENCES %s (%s)",
-			i.TableName, i.Name, i.Type, OrderedIndexFields(i.Fields), i.SourceTable, OrderedIndexFields(i.SourceFields))
+			QuoteIdentifier(i.TableName), QuoteIdentifier(i.Name), i.Type, QuotedIndexFields(i.Fields), QuoteIdentifier(i.SourceTable), QuotedIndexFields(i.SourceFields))
 		if i.OnDelete != "" {
 			definition += " ON DELETE " + i.OnDelete
 		}
 		if i.OnUpdate != "" {
 			definition += " ON UPDATE " + i.OnUpdate
 		}
+	case CHECK:
+		definition = fmt.Sprintf("ALTER TABLE %s ADD CONSTRAINT %s CHECK %s", QuoteIdentifier(i.TableName), QuoteIdentifier(i.Name), ParenthesizedExpression(i.Expression))
 	case INDEX:
 		var (
 			unique   string
 			computed string
 		)
-		fieldsDefinition := OrderedIndexFields(i.Fields)
+		fieldsDefinition := QuotedIndexFields(i.Fields)
 		if i.Unique {
 			unique = " UNIQUE"
 		}
@@ -106,12 +150,16 @@ func (i *Index) Definition(sep string) string {
 		if !i.Asc {
 			order = " DESCENDING"
 		}
-		definition = fmt.Sprintf("CREATE%s%s INDEX %s ON %s%s (%s)", unique, order, i.Name, i.TableName, computed, fieldsDefinition)
+		definition = fmt.Sprintf("CREATE%s%s INDEX %s ON %s%s (%s)", unique, order, QuoteIdentifier(i.Name), QuoteIdentifier(i.TableName), computed, fieldsDefinition)
 	}
 
 	return definition + sep
 }
 
+func QuotedIndexFields(fields map[string]IndexField) string {
+	return strings.Join(QuoteIdentifiers(strings.Split(OrderedIndexFields(fields), ",")), ",")
+}
+
 func OrderedIndexFields(fields map[string]IndexField) string {
 	var indexFields []IndexField
 	for _, indexField := range fields {
@@ -131,9 +179,14 @@ func OrderedIndexFields(fields map[string]IndexField) string {
 func (i *Index) Equals(i2 interface{}) bool {
 	other := i.CastType(i2)
 
-	if i.Name != other.Name || i.TableName != other.TableName || i.Computed != other.Computed ||
-		i.Expression != other.Expression || i.SourceTable != other.SourceTable || i.Comment != other.Comment ||
-		i.OnDelete != other.OnDelete || i.OnUpdate != other.OnUpdate || i.Asc != other.Asc || i.Active != other.Active {
+	return i.StructureEquals(other) && i.Comment == other.Comment && (!i.HasActivity() || i.Active == other.Active)
+}
+
+// StructureEquals compares everything which can't be changed without recreating the index.
+func (i *Index) StructureEquals(other *Index) bool {
+	if i.Name != other.Name || i.TableName != other.TableName || i.Computed != other.Computed || i.Unique != other.Unique ||
+		i.Expression != other.Expression || i.SourceTable != other.SourceTable ||
+		i.OnDelete != other.OnDelete || i.OnUpdate != other.OnUpdate || i.Asc != other.Asc {
 		return false
 	}
 
@@ -221,11 +274,11 @@ func (i *Index) String() string {
 }
 
 func IndexTypes() []string {
-	return []string{INDEX, PRIMARY_KEY, FOREIGN_KEY, UNIQUE}
+	return []string{INDEX, PRIMARY_KEY, FOREIGN_KEY, UNIQUE, CHECK}
 }
 
-func (i *Index) FetchIndexesFromDB(conn *sql.DB) (map[string]map[string]map[string]*Index, error) {
-
+func (i *Index) FetchIndexesFromDB(ctx context.Context, conn *sql.DB, filter *sqlrog.ElementFilter) (map[string]map[string]map[string]*Index, error) {
+	condition, args := FilterCondition(filter, "i.rdb$relation_name")
 	indexQuery := `select trim(i.rdb$relation_name), trim(coalesce(i.rdb$index_name, '')),
             trim(coalesce(i2.rdb$relation_name,'')),  trim(coalesce(s2.rdb$field_name,'')),
             trim(coalesce(c.rdb$constraint_type, 'INDEX')) as index_type,
@@ -246,9 +299,9 @@ func (i *Index) FetchIndexesFromDB(conn *sql.DB) (map[string]map[string]map[stri
             left join rdb$indices i2 on i.rdb$foreign_key = i2.rdb$index_name
             left join rdb$index_segments s2 on s2.rdb$index_name = i.rdb$foreign_key
             left join rdb$ref_constraints rf on rf.rdb$constraint_name = i.rdb$index_name
-            WHERE i.rdb$system_flag = 0 
+            WHERE i.rdb$system_flag = 0` + condition + `
             ORDER BY i.rdb$index_name`
-	indexRows, err := conn.Query(indexQuery)
+	indexRows, err := conn.QueryContext(ctx, indexQuery, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -294,9 +347,62 @@ func (i *Index) FetchIndexesFromDB(conn *sql.DB) (map[string]map[string]map[stri
 
 	indexRows.Close()
 
+	checks, err := i.FetchChecksFromDB(ctx, conn, filter)
+	if err != nil {
+		return nil, err
+	}
+	for _, check := range checks {
+		if _, ok := tableIndexes[check.TableName]; !ok {
+			tableIndexes[check.TableName] = make(map[string]map[string]*Index)
+		}
+		if _, ok := tableIndexes[check.TableName][CHECK]; !ok {
+			tableIndexes[check.TableName][CHECK] = make(map[string]*Index)
+		}
+		tableIndexes[check.TableName][CHECK][check.Name] = check
+	}
+
 	return tableIndexes, nil
 }
 
+// FetchChecksFromDB reads CHECK constraints, their conditions are kept in the source of
+// the "before insert" trigger Firebird creates for the constraint.
+func (i *Index) FetchChecksFromDB(ctx context.Context, conn *sql.DB, filter *sqlrog.ElementFilter) ([]*Index, error) {
+	var checks []*Index
+	condition, args := FilterCondition(filter, "rc.rdb$relation_name")
+	rows, err := conn.QueryContext(ctx, `
+		select trim(rc.rdb$relation_name), trim(rc.rdb$constraint_name), trim(coalesce(t.rdb$trigger_source, ''))
+		from rdb$relation_constraints rc
+		join rdb$check_constraints cc on cc.rdb$constraint_name = rc.rdb$constraint_name
+		join rdb$triggers t on t.rdb$trigger_name = cc.rdb$trigger_name and t.rdb$trigger_type = 1
+		where rc.rdb$constraint_type = 'CHECK'`+condition+`
+		order by rc.rdb$constraint_name`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		check := &Index{Type: CHECK, Fields: make(map[string]IndexField), SourceFields: make(map[string]IndexField), Asc: true, Active: true}
+		var source string
+		if err := rows.Scan(&check.TableName, &check.Name, &source); err != nil {
+			return nil, err
+		}
+		check.Expression = CheckExpression(source)
+		checks = append(checks, check)
+	}
+
+	return checks, rows.Err()
+}
+
+// CheckExpression strips CHECK keyword from the source of the constraint.
+func CheckExpression(source string) string {
+	source = strings.TrimSpace(source)
+	if len(source) >= len(CHECK) && strings.EqualFold(source[:len(CHECK)], CHECK) {
+		source = strings.TrimSpace(source[len(CHECK):])
+	}
+
+	return source
+}
+
 func (i *Index) DiffsOnDrop(schema sqlrog.ElementSchema) []*sqlrog.DiffObject {
 	return i.BaseElementSchema.DiffsOnDrop(schema)
 }
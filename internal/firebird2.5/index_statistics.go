@@ -0,0 +1,66 @@
+package fb
+
+import (
+	"sort"
+
+	"github.com/stpatrickw/sqlrog/internal/sqlrog"
+)
+
+const (
+	CORE_ELEMENT_INDEX_STATISTICS_NAME = "index_statistics"
+	INDEX_STATISTICS_PRIORITY          = -6
+)
+
+// IndexStatistics recomputes selectivity of indexes of tables which data is changed in bulk.
+type IndexStatistics struct {
+	sqlrog.BaseElementSchema `yaml:"base,omitempty"`
+	Indexes                  []*Index
+}
+
+func (is *IndexStatistics) GetName() string {
+	return "statistics"
+}
+
+func (is *IndexStatistics) GetTypeName() string {
+	return CORE_ELEMENT_INDEX_STATISTICS_NAME
+}
+
+func (is *IndexStatistics) CreateDefinition(sep string) []string {
+	var definitions []string
+	for _, index := range is.Indexes {
+		definitions = append(definitions, index.StatisticsDefinition(sep))
+	}
+
+	return definitions
+}
+
+func (fb *FirebirdEngine) StatisticsDiff(source *FbSchema, target *FbSchema) *sqlrog.DiffObject {
+	statistics := &IndexStatistics{}
+	targetData := target.TableData()
+	for name, table := range source.TableData().Tables {
+		if targetTable, ok := targetData.Tables[name]; ok && sqlrog.DataRowsEqual(table.GetData(), targetTable.GetData()) {
+			continue
+		}
+		for _, indexesByType := range table.(*Table).Indexes {
+			for _, index := range indexesByType {
+				if index.Type != CHECK && (index.Active || !index.HasActivity()) {
+					statistics.Indexes = append(statistics.Indexes, index)
+				}
+			}
+		}
+	}
+	if len(statistics.Indexes) == 0 {
+		return nil
+	}
+	sort.Slice(statistics.Indexes, func(i, j int) bool {
+		return statistics.Indexes[i].Name < statistics.Indexes[j].Name
+	})
+
+	return &sqlrog.DiffObject{
+		State:    sqlrog.DIFF_TYPE_CREATE,
+		Type:     statistics.GetTypeName(),
+		To:       statistics,
+		Priority: INDEX_STATISTICS_PRIORITY,
+		SqlOnly:  true,
+	}
+}
@@ -0,0 +1,96 @@
+package fb
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/stpatrickw/sqlrog/internal/sqlrog"
+)
+
+// GetLintRules has no foreign_key_without_index rule, Firebird creates an index for every foreign key itself.
+func (fb *FirebirdEngine) GetLintRules() []*sqlrog.LintRule {
+	return append(sqlrog.TableLintRules(), []*sqlrog.LintRule{
+		{
+			Name:        "mixed_collations",
+			Description: "Columns of a table should use the same collation",
+			Severity:    sqlrog.LINT_SEVERITY_INFO,
+			Check: func(schema sqlrog.ElementSchema, config *sqlrog.LintConfig) []*sqlrog.LintIssue {
+				var issues []*sqlrog.LintIssue
+				for _, element := range sqlrog.LintTables(schema) {
+					table := element.(*Table)
+					var collations []string
+					for _, column := range table.Fields {
+						collations = append(collations, column.Collate)
+					}
+					collation := sqlrog.PredominantValue(collations)
+					for _, column := range OrderedColumnFields(table.Fields) {
+						if column.Collate != "" && column.Collate != collation {
+							issues = append(issues, sqlrog.TableLintIssue(table, fmt.Sprintf("column %s uses %s collation, other columns use %s", column.Name, column.Collate, collation)))
+						}
+					}
+				}
+				return issues
+			},
+		},
+	}...)
+}
+
+func (t *Table) HasPrimaryKey() bool {
+	return len(t.Indexes[PRIMARY_KEY]) > 0
+}
+
+func (t *Table) LintColumns() []*sqlrog.LintColumn {
+	var columns []*sqlrog.LintColumn
+	for _, column := range OrderedColumnFields(t.Fields) {
+		columns = append(columns, &sqlrog.LintColumn{Name: column.Name, Charset: column.Charset, NotNull: column.NotNull})
+	}
+
+	return columns
+}
+
+// LintCharsets guesses the database charset by the column charsets, Firebird tables have no own charset.
+func (t *Table) LintCharsets() []string {
+	var charsets []string
+	for _, column := range t.Fields {
+		charsets = append(charsets, column.Charset)
+	}
+
+	return charsets
+}
+
+// LintUniqueIndexes skips expression indexes, they have no columns to check.
+func (t *Table) LintUniqueIndexes() []*sqlrog.LintIndex {
+	var indexes []*sqlrog.LintIndex
+	for _, indexType := range []string{UNIQUE, INDEX} {
+		for _, index := range t.Indexes[indexType] {
+			if indexType == INDEX && (!index.Unique || index.Computed) {
+				continue
+			}
+			indexes = append(indexes, &sqlrog.LintIndex{Name: index.Name, Fields: strings.Split(OrderedIndexFields(index.Fields), ",")})
+		}
+	}
+
+	return indexes
+}
+
+// LintChildren lets the naming rule check columns, indexes, constraints and table triggers.
+func (t *Table) LintChildren() []*sqlrog.LintChild {
+	var children []*sqlrog.LintChild
+	for _, column := range OrderedColumnFields(t.Fields) {
+		children = append(children, &sqlrog.LintChild{Type: column.GetTypeName(), Name: column.Name})
+	}
+	for _, indexes := range t.Indexes {
+		for _, index := range indexes {
+			children = append(children, &sqlrog.LintChild{Type: index.GetTypeName(), Name: index.Name})
+		}
+	}
+	for _, trigger := range t.Triggers {
+		children = append(children, &sqlrog.LintChild{Type: trigger.GetTypeName(), Name: trigger.Name})
+	}
+
+	return children
+}
+
+func (p *Procedure) LintSource() string {
+	return p.Source
+}
@@ -0,0 +1,8 @@
+package fb
+
+import "strings"
+
+// StringLiteral puts the value in single quotes, Firebird escapes only quotes by doubling them.
+func StringLiteral(value string) string {
+	return "'" + strings.Replace(value, "'", "''", -1) + "'"
+}
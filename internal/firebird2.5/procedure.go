@@ -2,15 +2,22 @@ package fb
 
 import (
 	"bytes"
+	"context"
 	"database/sql"
 	"fmt"
 	"github.com/stpatrickw/sqlrog/internal/sqlrog"
+	"sort"
+	"strings"
 	"text/template"
 )
 
 const (
 	CORE_ELEMENT_PROCEDURE_NAME        = "procedure"
 	CORE_ELEMENT_PROCEDURE_PLURAL_NAME = "procedures"
+	PROCEDURE_STUB_PRIORITY            = -1
+	PROCEDURE_PRIORITY                 = -3
+	PROCEDURE_DROP_PRIORITY            = -7
+	PROCEDURE_STUB_SOURCE              = "BEGIN\nEND"
 )
 
 type Procedure struct {
@@ -22,9 +29,57 @@ type Procedure struct {
 }
 
 type ProcedureParameter struct {
-	Name     string
-	TypeName string
-	Position int
+	Name         string
+	TypeName     string
+	Position     int
+	TypeOf       bool   `yaml:"typeof,omitempty"`
+	TypeOfColumn string `yaml:"typeofcolumn,omitempty"`
+	NotNull      bool   `yaml:"notnull,omitempty"`
+	Collate      string `yaml:"collate,omitempty"`
+	Default      string `yaml:"default,omitempty"`
+}
+
+func (pp *ProcedureParameter) Definition() string {
+	definition := QuoteIdentifier(pp.Name) + " "
+	switch {
+	case pp.TypeOfColumn != "":
+		definition += "TYPE OF COLUMN " + strings.Join(QuoteIdentifiers(strings.SplitN(pp.TypeOfColumn, ".", 2)), ".")
+	case pp.TypeOf:
+		definition += "TYPE OF " + QuoteIdentifier(pp.TypeName)
+	default:
+		definition += pp.TypeName
+	}
+	if pp.NotNull {
+		definition += " NOT NULL"
+	}
+	if pp.Collate != "" {
+		definition += " COLLATE " + pp.Collate
+	}
+	if pp.Default != "" {
+		definition += " " + pp.Default
+	}
+
+	return definition
+}
+
+func OrderedProcedureParameters(params map[string]*ProcedureParameter) []*ProcedureParameter {
+	var ordered []*ProcedureParameter
+	for _, param := range params {
+		ordered = append(ordered, param)
+	}
+	sort.Slice(ordered, func(i, j int) bool {
+		return ordered[i].Position < ordered[j].Position
+	})
+
+	return ordered
+}
+
+func (p *Procedure) OrderedInputParameters() []*ProcedureParameter {
+	return OrderedProcedureParameters(p.InputParameters)
+}
+
+func (p *Procedure) OrderedOutputParameters() []*ProcedureParameter {
+	return OrderedProcedureParameters(p.OutputParameters)
 }
 
 func (p *Procedure) GetName() string {
@@ -39,8 +94,14 @@ func (p *Procedure) GetPluralTypeName() string {
 	return CORE_ELEMENT_PROCEDURE_PLURAL_NAME
 }
 
+// GetPriority puts procedure bodies after views, which could be used by procedures,
+// and after stubs of all new procedures.
+func (p *Procedure) GetPriority() int {
+	return PROCEDURE_PRIORITY
+}
+
 func (p *Procedure) AlterDefinition(other interface{}, sep string) []string {
-	return []string{fmt.Sprintf("ALTER %s", p.CastType(other).Definition(sep))}
+	return []string{fmt.Sprintf("ALTER %s", p.Definition(sep))}
 }
 
 func (p *Procedure) CreateDefinition(sep string) []string {
@@ -48,16 +109,16 @@ func (p *Procedure) CreateDefinition(sep string) []string {
 }
 
 func (p *Procedure) DropDefinition(sep string) []string {
-	return []string{fmt.Sprintf("DROP PROCEDURE %s%s", p.Name, sep)}
+	return []string{fmt.Sprintf("DROP PROCEDURE %s%s", QuoteIdentifier(p.Name), sep)}
 }
 
 func (p *Procedure) Definition(sep string) string {
-	procTmpl, err := template.New("procedure").Parse(`PROCEDURE {{ .Name}} {{if .InputParameters}}(
-	{{$first := true}}{{range $index, $element := .InputParameters}}{{if $first}}{{$first = false}}{{else}},
-	{{end}}{{.Name}} {{.TypeName}}{{end}}) {{end}}{{if .OutputParameters}}
+	procTmpl, err := template.New("procedure").Funcs(templateFuncs).Parse(`PROCEDURE {{ quote .Name}} {{if .InputParameters}}(
+	{{$first := true}}{{range .OrderedInputParameters}}{{if $first}}{{$first = false}}{{else}},
+	{{end}}{{ .Definition}}{{end}}) {{end}}{{if .OutputParameters}}
 returns (
-	{{$first := true}}{{range $index, $element := .OutputParameters}}{{if $first}}{{$first = false}}{{else}},
-	{{end}}{{.Name}} {{.TypeName}}{{end}}){{end}}
+	{{$first := true}}{{range .OrderedOutputParameters}}{{if $first}}{{$first = false}}{{else}},
+	{{end}}{{ .Definition}}{{end}}){{end}}
 as
 {{ .Source }}`)
 
@@ -97,16 +158,25 @@ func (p *Procedure) Diff(e2 interface{}) *sqlrog.DiffObject {
 
 	if !p.Equals(other) {
 		return &sqlrog.DiffObject{
-			State: sqlrog.DIFF_TYPE_UPDATE,
-			Type:  p.GetTypeName(),
-			From:  p,
-			To:    other,
+			State:    sqlrog.DIFF_TYPE_UPDATE,
+			Type:     p.GetTypeName(),
+			From:     p,
+			To:       other,
+			Priority: p.GetPriority(),
 		}
 	}
 
 	return nil
 }
 
+// Stub returns the procedure with the same parameters and an empty body.
+func (p *Procedure) Stub() *Procedure {
+	stub := *p
+	stub.Source = PROCEDURE_STUB_SOURCE
+
+	return &stub
+}
+
 func ProcedureParamsEquals(src map[string]*ProcedureParameter, dest map[string]*ProcedureParameter) bool {
 	if len(src) != len(dest) {
 		return false
@@ -129,23 +199,26 @@ func ProcedureParamsEquals(src map[string]*ProcedureParameter, dest map[string]*
 }
 
 func ParamEquals(src *ProcedureParameter, dest *ProcedureParameter) bool {
-	return src.Name == dest.Name && src.Position == dest.Position && src.TypeName == dest.TypeName
+	return *src == *dest
 }
 
 func (p *Procedure) CastType(other interface{}) *Procedure {
 	return other.(*Procedure)
 }
 
-func (p *Procedure) FetchElementsFromDB(conn *sql.DB) ([]sqlrog.ElementSchema, error) {
+func (p *Procedure) FetchElementsFromDB(ctx context.Context, conn *sql.DB, filter *sqlrog.ElementFilter) ([]sqlrog.ElementSchema, error) {
 	var procedures []sqlrog.ElementSchema
 
-	rows, err := conn.Query(`
+	condition, args := FilterCondition(filter, "rdb$procedure_name")
+
+	rows, err := conn.QueryContext(ctx, `
 		select trim(rdb$procedure_name), rdb$procedure_source
-		from rdb$procedures order by 1`)
+		from rdb$procedures where 1 = 1`+condition+` order by 1`, args...)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
+	parametersCondition, parametersArgs := FilterCondition(filter, "RF.RDB$PROCEDURE_NAME")
 	parametersQuery := `SELECT
        	      TRIM(rdb$procedure_name),
        	      rdb$parameter_type,
@@ -183,15 +256,20 @@ func (p *Procedure) FetchElementsFromDB(conn *sql.DB) ([]sqlrog.ElementSchema, e
                 WHEN 261 THEN 'BLOB SUB_TYPE ' || F.RDB$FIELD_SUB_TYPE
                 ELSE 'RDB$FIELD_TYPE: ' || F.RDB$FIELD_TYPE || '?'
               END END) FIELD_TYPE,
-              RF.RDB$PARAMETER_NUMBER
+              RF.RDB$PARAMETER_NUMBER,
+              CASE WHEN COALESCE(RF.RDB$PARAMETER_MECHANISM, 0) = 1 AND RF.RDB$RELATION_NAME IS NULL THEN 1 ELSE 0 END,
+              TRIM(COALESCE(TRIM(RF.RDB$RELATION_NAME) || '.' || TRIM(RF.RDB$FIELD_NAME), '')),
+              COALESCE(RF.RDB$NULL_FLAG, 0),
+              TRIM(COALESCE(DCO.RDB$COLLATION_NAME, '')),
+              TRIM(COALESCE(RF.RDB$DEFAULT_SOURCE, ''))
             FROM RDB$PROCEDURE_PARAMETERS RF
             JOIN RDB$FIELDS F ON (F.RDB$FIELD_NAME = RF.RDB$FIELD_SOURCE)
             LEFT OUTER JOIN RDB$CHARACTER_SETS CH ON (CH.RDB$CHARACTER_SET_ID = F.RDB$CHARACTER_SET_ID)
-            LEFT OUTER JOIN RDB$COLLATIONS DCO ON ((DCO.RDB$COLLATION_ID = F.RDB$COLLATION_ID) AND (DCO.RDB$CHARACTER_SET_ID = F.RDB$CHARACTER_SET_ID))
-            WHERE COALESCE(RF.RDB$SYSTEM_FLAG, 0) = 0
+            LEFT OUTER JOIN RDB$COLLATIONS DCO ON ((DCO.RDB$COLLATION_ID = RF.RDB$COLLATION_ID) AND (DCO.RDB$CHARACTER_SET_ID = F.RDB$CHARACTER_SET_ID))
+            WHERE COALESCE(RF.RDB$SYSTEM_FLAG, 0) = 0` + parametersCondition + `
             ORDER BY RF.RDB$PARAMETER_NUMBER`
 
-	parameterRows, err := conn.Query(parametersQuery)
+	parameterRows, err := conn.QueryContext(ctx, parametersQuery, parametersArgs...)
 	if err != nil {
 		return procedures, err
 	}
@@ -201,7 +279,8 @@ func (p *Procedure) FetchElementsFromDB(conn *sql.DB) ([]sqlrog.ElementSchema, e
 		var paramType int
 		var procedureName string
 		procedureParam := &ProcedureParameter{}
-		err := parameterRows.Scan(&procedureName, &paramType, &procedureParam.Name, &procedureParam.TypeName, &procedureParam.Position)
+		err := parameterRows.Scan(&procedureName, &paramType, &procedureParam.Name, &procedureParam.TypeName, &procedureParam.Position,
+			&procedureParam.TypeOf, &procedureParam.TypeOfColumn, &procedureParam.NotNull, &procedureParam.Collate, &procedureParam.Default)
 		if err != nil {
 			return nil, err
 		}
@@ -237,10 +316,35 @@ func (p *Procedure) FetchElementsFromDB(conn *sql.DB) ([]sqlrog.ElementSchema, e
 	return procedures, nil
 }
 
+// DiffsOnCreate creates a stub of the procedure first and alters it with the real body later,
+// so procedures calling each other could be created in any order.
 func (p *Procedure) DiffsOnCreate(schema sqlrog.ElementSchema) []*sqlrog.DiffObject {
-	return p.BaseElementSchema.DiffsOnCreate(schema)
+	stub := p.Stub()
+
+	return []*sqlrog.DiffObject{
+		{
+			State:    sqlrog.DIFF_TYPE_CREATE,
+			Type:     p.GetTypeName(),
+			To:       stub,
+			Priority: PROCEDURE_STUB_PRIORITY,
+			SqlOnly:  true,
+		},
+		{
+			State:    sqlrog.DIFF_TYPE_UPDATE,
+			Type:     p.GetTypeName(),
+			From:     p,
+			To:       stub,
+			Priority: p.GetPriority(),
+		},
+	}
 }
 
+// DiffsOnDrop drops the procedure after views, triggers and grants using it are changed.
 func (p *Procedure) DiffsOnDrop(schema sqlrog.ElementSchema) []*sqlrog.DiffObject {
-	return p.BaseElementSchema.DiffsOnDrop(schema)
+	diffs := p.BaseElementSchema.DiffsOnDrop(schema)
+	for _, diff := range diffs {
+		diff.Priority = PROCEDURE_DROP_PRIORITY
+	}
+
+	return diffs
 }
@@ -1,6 +1,7 @@
 package fb
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
 	"github.com/stpatrickw/sqlrog/internal/sqlrog"
@@ -41,7 +42,7 @@ func (r *Role) DropDefinition(sep string) []string {
 }
 
 func (r *Role) Definition(sep string) string {
-	return fmt.Sprintf("ROLE %s%s", r.Name, sep)
+	return fmt.Sprintf("ROLE %s%s", QuoteIdentifier(r.Name), sep)
 }
 
 func (r *Role) Equals(e2 interface{}) bool {
@@ -69,10 +70,12 @@ func (r *Role) CastType(other interface{}) *Role {
 	return other.(*Role)
 }
 
-func (r *Role) FetchElementsFromDB(conn *sql.DB) ([]sqlrog.ElementSchema, error) {
+func (r *Role) FetchElementsFromDB(ctx context.Context, conn *sql.DB, filter *sqlrog.ElementFilter) ([]sqlrog.ElementSchema, error) {
 	var roles []sqlrog.ElementSchema
 
-	rows, err := conn.Query(`SELECT trim(rdb$role_name) FROM RDB$ROLES WHERE rdb$system_flag = 0 order by 1`)
+	condition, args := FilterCondition(filter, "rdb$role_name")
+
+	rows, err := conn.QueryContext(ctx, `SELECT trim(rdb$role_name) FROM RDB$ROLES WHERE rdb$system_flag = 0`+condition+` order by 1`, args...)
 	if err != nil {
 		return roles, err
 	}
@@ -1,10 +1,12 @@
 package fb
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
 	"os"
 	"reflect"
+	"regexp"
 
 	_ "github.com/nakagami/firebirdsql"
 	"github.com/pkg/errors"
@@ -62,11 +64,12 @@ func (fb *FirebirdEngine) CreateParams() interface{} {
 	return &FbParams{}
 }
 
-func (fb *FirebirdEngine) LoadSchema(config *sqlrog.Config, reader sqlrog.ObjectReader) (sqlrog.ElementSchema, error) {
+func (fb *FirebirdEngine) LoadSchema(ctx context.Context, config *sqlrog.Config, reader sqlrog.ObjectReader, filter *sqlrog.ElementFilter) (sqlrog.ElementSchema, error) {
 	schema := &FbSchema{
-		sqlrog.BaseElementSchema{
+		BaseElementSchema: sqlrog.BaseElementSchema{
 			CoreElements: make(map[string]map[string]sqlrog.ElementSchema),
 		},
+		Statistics: config.Statistics,
 	}
 
 	var schemaElements []sqlrog.ElementSchema
@@ -75,22 +78,36 @@ func (fb *FirebirdEngine) LoadSchema(config *sqlrog.Config, reader sqlrog.Object
 			return nil, errors.New(fmt.Sprintf("Folder for Project: %s doesn't exist", config.ProjectName))
 		}
 
-		elements, err := fb.LoadElementsFromFiles(config.ProjectName, schema, reader)
+		elements, err := fb.LoadElementsFromFiles(config.ProjectName, schema, reader, filter)
 		if err != nil {
 			return nil, err
 		}
 		schemaElements = append(schemaElements, elements...)
+		if config.Grants {
+			grants, err := fb.LoadOptionalElementTypeFromFiles(config.ProjectName, &Grant{}, reader, filter)
+			if err != nil {
+				return nil, err
+			}
+			schemaElements = append(schemaElements, grants...)
+		}
 
 	} else {
 		conn, err := fb.OpenConnection(config.Params.(*FbParams))
 		if err != nil {
 			return nil, err
 		}
-		elements, err := schema.FetchElementsFromDB(conn)
+		elements, err := schema.FetchElementsFromDB(ctx, conn, filter)
 		if err != nil {
 			return nil, err
 		}
 		schemaElements = append(schemaElements, elements...)
+		if config.Grants {
+			grants, err := (&Grant{}).FetchElementsFromDB(ctx, conn, filter)
+			if err != nil {
+				return nil, err
+			}
+			schemaElements = append(schemaElements, grants...)
+		}
 
 		fb.CloseConnection(conn)
 	}
@@ -102,11 +119,39 @@ func (fb *FirebirdEngine) LoadSchema(config *sqlrog.Config, reader sqlrog.Object
 		}
 	}
 
+	if config.AppType != sqlrog.ProjectTypeFile && config.GeneratorValues != "" {
+		conn, err := fb.OpenConnection(config.Params.(*FbParams))
+		if err != nil {
+			return nil, err
+		}
+		err = (&Generator{}).FetchValuesFromDB(ctx, conn, schema.CoreElements[CORE_ELEMENT_GENERATOR_NAME], config.GeneratorValues)
+		fb.CloseConnection(conn)
+		if err != nil {
+			return nil, err
+		}
+	}
+	schema.SetGeneratorValuesPolicy(config.GeneratorValues)
+
+	if config.AppType == sqlrog.ProjectTypeFile {
+		schema.TrackData(config)
+	} else if len(config.Data) > 0 {
+		conn, err := fb.OpenConnection(config.Params.(*FbParams))
+		if err != nil {
+			return nil, err
+		}
+		err = schema.FetchDataFromDB(ctx, conn, config)
+		fb.CloseConnection(conn)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	return schema, nil
 }
 
 type FbSchema struct {
 	sqlrog.BaseElementSchema
+	Statistics bool
 }
 
 func (fbs *FbSchema) GetChilds() []sqlrog.ElementSchema {
@@ -120,19 +165,23 @@ func (fbs *FbSchema) GetChilds() []sqlrog.ElementSchema {
 }
 
 func (fbs *FbSchema) GetGlobalChildElements() []sqlrog.ElementSchema {
-	return []sqlrog.ElementSchema{&Domain{}, &Exception{}, &Generator{}, &Role{}, &Procedure{}, &View{}, &Table{}}
+	return []sqlrog.ElementSchema{&Domain{}, &Exception{}, &Generator{}, &Role{}, &ExternalFunction{}, &BlobFilter{}, &Procedure{}, &View{}, &Table{}, &Trigger{}}
 }
 
-func (fb *FirebirdEngine) ExecuteSQL(config *sqlrog.Config, sqls []string) error {
+func (fb *FirebirdEngine) ExecuteSQL(ctx context.Context, config *sqlrog.Config, sqls []string) error {
 	conn, err := fb.OpenConnection(config.Params.(*FbParams))
 	if err != nil {
 		return err
 	}
 	defer fb.CloseConnection(conn)
-	for _, stmt := range sqls {
-		_, err = conn.Exec(stmt)
+	for i, stmt := range sqls {
+		// a cancelled run stops before the next statement, so long batches like data diffs can be interrupted
+		if err := ctx.Err(); err != nil {
+			return &sqlrog.StatementError{Index: i, Err: err}
+		}
+		_, err = conn.ExecContext(ctx, stmt)
 		if err != nil {
-			return err
+			return &sqlrog.StatementError{Index: i, Err: err}
 		}
 	}
 	return nil
@@ -147,10 +196,10 @@ func (fbs *FbSchema) AddChild(child sqlrog.ElementSchema) error {
 	return nil
 }
 
-func (fbs *FbSchema) FetchElementsFromDB(conn *sql.DB) ([]sqlrog.ElementSchema, error) {
+func (fbs *FbSchema) FetchElementsFromDB(ctx context.Context, conn *sql.DB, filter *sqlrog.ElementFilter) ([]sqlrog.ElementSchema, error) {
 	var elements []sqlrog.ElementSchema
 	for _, el := range fbs.GetGlobalChildElements() {
-		fetchedElements, err := el.FetchElementsFromDB(conn)
+		fetchedElements, err := el.FetchElementsFromDB(ctx, conn, filter)
 		if err != nil {
 			return nil, err
 		}
@@ -163,6 +212,31 @@ func (fbs *FbSchema) String() string {
 	return "schema"
 }
 
+func FilterCondition(filter *sqlrog.ElementFilter, column string) (string, []interface{}) {
+	return filter.Condition(column, "%s CONTAINING ?", "NOT (%s CONTAINING ?)")
+}
+
+// ScriptRenderer switches the terminator of isql for procedures, triggers and EXECUTE BLOCK.
+func (fb *FirebirdEngine) ScriptRenderer() *sqlrog.ScriptRenderer {
+	return &sqlrog.ScriptRenderer{
+		Delimiter:         sqlrog.DEFAULT_SQL_SEP,
+		CompoundDelimiter: "^",
+		SetDelimiter: func(delimiter string, current string) string {
+			return fmt.Sprintf("SET TERM %s %s", delimiter, current)
+		},
+	}
+}
+
+// ScriptParser reads isql scripts with SET TERM commands.
+func (fb *FirebirdEngine) ScriptParser() *sqlrog.ScriptParser {
+	return &sqlrog.ScriptParser{
+		Delimiter:          sqlrog.DEFAULT_SQL_SEP,
+		Quotes:             "'\"",
+		LineComments:       []string{"--"},
+		DelimiterStatement: regexp.MustCompile(`(?is)^SET\s+TERM\s+(\S+)$`),
+	}
+}
+
 func (fb *FirebirdEngine) OpenConnection(params *FbParams) (*sql.DB, error) {
 	connectionString := fmt.Sprintf("%s:%s@%s:%s/%s",
 		params.GetParam("User"),
@@ -185,6 +259,16 @@ func (e *FirebirdEngine) SchemaDiff(source interface{}, target interface{}) []*s
 	for _, el := range sourceSchema.GetGlobalChildElements() {
 		changes = append(changes, e.CompareScheme(sourceSchema.CoreElements[el.GetTypeName()], targetSchema.CoreElements[el.GetTypeName()])...)
 	}
+	changes = append(changes, e.TableTriggersDiff(sourceSchema, targetSchema)...)
+	changes = append(changes, e.CompareScheme(sourceSchema.CoreElements[CORE_ELEMENT_GRANT_NAME], targetSchema.CoreElements[CORE_ELEMENT_GRANT_NAME])...)
+	if dataDiff := e.DataDiff(sourceSchema, targetSchema); dataDiff != nil {
+		changes = append(changes, dataDiff)
+		if targetSchema.Statistics {
+			if statisticsDiff := e.StatisticsDiff(sourceSchema, targetSchema); statisticsDiff != nil {
+				changes = append(changes, statisticsDiff)
+			}
+		}
+	}
 
 	return changes
 }
@@ -0,0 +1,856 @@
+package fb
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"io"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/stpatrickw/sqlrog/internal/sqlrog"
+)
+
+var fbEngine FirebirdEngine
+
+func newSchema(elements ...sqlrog.ElementSchema) *FbSchema {
+	schema := &FbSchema{}
+	schema.CoreElements = make(map[string]map[string]sqlrog.ElementSchema)
+	for _, element := range elements {
+		schema.AddChild(element)
+	}
+
+	return schema
+}
+
+// fakeResult is returned by the fake database for queries containing Match.
+type fakeResult struct {
+	Match   string
+	Columns []string
+	Rows    [][]driver.Value
+}
+
+// fakeDB answers catalog queries with prepared results and records every query with its arguments,
+// so fetching is tested without a Firebird server.
+type fakeDB struct {
+	results []*fakeResult
+	queries []string
+	args    [][]driver.Value
+}
+
+var fakeDatabases = make(map[string]*fakeDB)
+
+func init() {
+	sql.Register("fbfake", fakeDriver{})
+}
+
+func openFakeDB(t *testing.T, results ...*fakeResult) (*sql.DB, *fakeDB) {
+	db := &fakeDB{results: results}
+	fakeDatabases[t.Name()] = db
+	conn, err := sql.Open("fbfake", t.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return conn, db
+}
+
+type fakeDriver struct{}
+
+func (fakeDriver) Open(name string) (driver.Conn, error) {
+	return &fakeConn{db: fakeDatabases[name]}, nil
+}
+
+type fakeConn struct {
+	db *fakeDB
+}
+
+func (c *fakeConn) Prepare(query string) (driver.Stmt, error) {
+	return &fakeStmt{db: c.db, query: query}, nil
+}
+
+func (c *fakeConn) Close() error {
+	return nil
+}
+
+func (c *fakeConn) Begin() (driver.Tx, error) {
+	return nil, driver.ErrSkip
+}
+
+type fakeStmt struct {
+	db    *fakeDB
+	query string
+}
+
+func (s *fakeStmt) Close() error {
+	return nil
+}
+
+func (s *fakeStmt) NumInput() int {
+	return -1
+}
+
+func (s *fakeStmt) Exec(args []driver.Value) (driver.Result, error) {
+	return nil, driver.ErrSkip
+}
+
+func (s *fakeStmt) Query(args []driver.Value) (driver.Rows, error) {
+	s.db.queries = append(s.db.queries, s.query)
+	s.db.args = append(s.db.args, args)
+	for _, result := range s.db.results {
+		if strings.Contains(s.query, result.Match) {
+			return &fakeRows{result: result}, nil
+		}
+	}
+
+	return &fakeRows{result: &fakeResult{}}, nil
+}
+
+type fakeRows struct {
+	result *fakeResult
+	next   int
+}
+
+func (r *fakeRows) Columns() []string {
+	return r.result.Columns
+}
+
+func (r *fakeRows) Close() error {
+	return nil
+}
+
+func (r *fakeRows) Next(dest []driver.Value) error {
+	if r.next >= len(r.result.Rows) {
+		return io.EOF
+	}
+	copy(dest, r.result.Rows[r.next])
+	r.next++
+
+	return nil
+}
+
+func sortedDiffSql(diffs []*sqlrog.DiffObject) []string {
+	sort.SliceStable(diffs, func(i, j int) bool {
+		return diffs[i].Priority > diffs[j].Priority
+	})
+	var sqls []string
+	for _, diff := range diffs {
+		sqls = append(sqls, diff.DiffSql(sqlrog.DEFAULT_SQL_SEP)...)
+	}
+
+	return sqls
+}
+
+func TestExternalFunctionDropOrder(t *testing.T) {
+	function := &ExternalFunction{
+		Name:       "ADDDAY",
+		Arguments:  []*ExternalFunctionArgument{{Type: "TIMESTAMP", Mechanism: "BY DESCRIPTOR"}},
+		Returns:    &ExternalFunctionArgument{Type: "TIMESTAMP", Mechanism: "BY DESCRIPTOR"},
+		EntryPoint: "addDay",
+		ModuleName: "fbudf",
+	}
+	blobFilter := &BlobFilter{Name: "DESC_FILTER", InputType: 1, OutputType: -4, EntryPoint: "desc_filter", ModuleName: "FILTERLIB"}
+	procedure := &Procedure{Name: "NEXT_DAY", Source: "BEGIN\n  D = ADDDAY(:D, 1);\nEND"}
+	trigger := &Trigger{Name: "ORDERS_BI", TableName: "ORDERS", TypeName: "BEFORE INSERT", Source: "AS BEGIN NEW.DUE = ADDDAY(NEW.CREATED, 1); END", Active: true}
+
+	sqls := sortedDiffSql(fbEngine.SchemaDiff(newSchema(), newSchema(function, blobFilter, procedure, trigger)))
+	position := func(prefix string) int {
+		for i, sql := range sqls {
+			if strings.HasPrefix(sql, prefix) {
+				return i
+			}
+		}
+		t.Fatalf("Expected statement is missing: %s\n%s\n", prefix, strings.Join(sqls, "\n"))
+		return -1
+	}
+	for _, dependent := range []string{"DROP PROCEDURE NEXT_DAY", "DROP TRIGGER ORDERS_BI"} {
+		for _, dependency := range []string{"DROP EXTERNAL FUNCTION ADDDAY", "DROP FILTER DESC_FILTER"} {
+			if position(dependent) > position(dependency) {
+				t.Errorf("Expected %s to run before %s:\n%s\n", dependent, dependency, strings.Join(sqls, "\n"))
+			}
+		}
+	}
+}
+
+func TestStringLiteralsSQL(t *testing.T) {
+	text := "it's a \"quoted\"\nmulti-line text with \\ backslash"
+	literal := "'it''s a \"quoted\"\nmulti-line text with \\ backslash'"
+	if sql := StringLiteral(text); sql != literal {
+		t.Errorf("Expected literal is not equal to real: \n%s\n%s\n", literal, sql)
+	}
+
+	exception := &Exception{Name: "E_NOT_FOUND", Message: text}
+	expected := "EXCEPTION E_NOT_FOUND " + literal + ";"
+	if sql := exception.Definition(sqlrog.DEFAULT_SQL_SEP); sql != expected {
+		t.Errorf("Expected exception sql is not equal to real: \n%s\n%s\n", expected, sql)
+	}
+	domain := &Domain{Name: "D_TEXT", Type: "VARCHAR(100)", Comment: text}
+	expected = "COMMENT ON DOMAIN D_TEXT IS " + literal + ";"
+	if sql := domain.AddComment(sqlrog.DEFAULT_SQL_SEP); sql != expected {
+		t.Errorf("Expected domain comment sql is not equal to real: \n%s\n%s\n", expected, sql)
+	}
+	table := &Table{Name: "orders"}
+	expected = "COMMENT ON COLUMN \"orders\".NOTE IS " + literal + ";\n"
+	if sql := table.CommentOnColumn(&TableColumn{Name: "NOTE", Comment: text}, sqlrog.DEFAULT_SQL_SEP); sql != expected {
+		t.Errorf("Expected column comment sql is not equal to real: \n%s\n%s\n", expected, sql)
+	}
+}
+
+func TestTypeChangeSupported(t *testing.T) {
+	tests := []struct {
+		from      string
+		to        string
+		supported bool
+	}{
+		{"VARCHAR(10)", "VARCHAR(20)", true},
+		{"CHAR(10)", "VARCHAR(10)", true},
+		{"VARCHAR(20)", "VARCHAR(10)", false},
+		{"SMALLINT", "INTEGER", true},
+		{"INTEGER", "BIGINT", true},
+		{"BIGINT", "INTEGER", false},
+		{"INTEGER", "VARCHAR(11)", true},
+		{"INTEGER", "VARCHAR(10)", false},
+		{"NUMERIC(9,2)", "NUMERIC(18,2)", true},
+		{"NUMERIC(9,2)", "DECIMAL(9,3)", false},
+		{"NUMERIC(9,4)", "NUMERIC(12,2)", false},
+		{"NUMERIC(9,2)", "VARCHAR(11)", true},
+		{"NUMERIC(9,2)", "VARCHAR(10)", false},
+		{"INTEGER", "NUMERIC(12,2)", true},
+		{"INTEGER", "NUMERIC(9,2)", false},
+		{"NUMERIC(9,2)", "INTEGER", false},
+		{"FLOAT", "DOUBLE", true},
+		{"INTEGER", "DOUBLE PRECISION", true},
+		{"DOUBLE", "FLOAT", false},
+		{"SMALLINT", "FLOAT", true},
+		{"INTEGER", "FLOAT", false},
+		{"DATE", "TIMESTAMP", true},
+		{"TIMESTAMP", "DATE", false},
+		{"TIMESTAMP", "VARCHAR(24)", true},
+		{"DATE", "VARCHAR(10)", false},
+		{"VARCHAR(10)", "INTEGER", false},
+		{"BLOB SUB_TYPE 1", "VARCHAR(100)", false},
+		{"VARCHAR(100)", "BLOB SUB_TYPE 1", false},
+	}
+	for _, test := range tests {
+		if supported := TypeChangeSupported(test.from, test.to); supported != test.supported {
+			t.Errorf("Expected %s -> %s supported to be %v\n", test.from, test.to, test.supported)
+		}
+	}
+	from := &TableColumn{Type: "VARCHAR(10)", Charset: "WIN1252"}
+	if ColumnTypeChangeSupported(from, &TableColumn{Type: "VARCHAR(20)", Charset: "UTF8"}) {
+		t.Errorf("Expected character set change to be refused\n")
+	}
+}
+
+func TestDefaultValue(t *testing.T) {
+	tests := map[string]string{
+		"":                  "",
+		"DEFAULT 0":         "0",
+		"default 'it''s'":   "'it''s'",
+		"  DEFAULT  NULL  ": "NULL",
+		"'DEFAULT'":         "'DEFAULT'",
+	}
+	for source, expected := range tests {
+		if value := DefaultValue(source); value != expected {
+			t.Errorf("Expected default value of %q to be %q, got %q\n", source, expected, value)
+		}
+	}
+}
+
+func TestAlterColumnSQL(t *testing.T) {
+	table := &Table{Name: "ORDERS"}
+	tests := []struct {
+		name     string
+		target   *TableColumn
+		source   *TableColumn
+		expected []string
+	}{
+		{
+			name:   "widening",
+			target: &TableColumn{Name: "CODE", Type: "VARCHAR(10)", Position: 2},
+			source: &TableColumn{Name: "CODE", Type: "VARCHAR(20)", Position: 2},
+			expected: []string{
+				"ALTER TABLE ORDERS ALTER COLUMN CODE TYPE VARCHAR(20);\n",
+			},
+		},
+		{
+			name:   "rebuild",
+			target: &TableColumn{Name: "CODE", Type: "VARCHAR(20)", Position: 2},
+			source: &TableColumn{Name: "CODE", Type: "VARCHAR(10)", Collate: "UNICODE_CI", Default: "DEFAULT ''", NotNull: true, Comment: "short code", Position: 2},
+			expected: []string{
+				"ALTER TABLE ORDERS ADD SQLROG$REBUILD VARCHAR(10) COLLATE UNICODE_CI;\n",
+				"UPDATE ORDERS SET SQLROG$REBUILD = CAST(CODE AS VARCHAR(10));\n",
+				"ALTER TABLE ORDERS DROP CODE;\n",
+				"ALTER TABLE ORDERS ALTER SQLROG$REBUILD TO CODE;\n",
+				"ALTER TABLE ORDERS ALTER CODE POSITION 2;\n",
+				"ALTER TABLE ORDERS ALTER COLUMN CODE SET DEFAULT '';\n",
+				"UPDATE ORDERS SET CODE = '' WHERE CODE IS NULL;\n",
+				"EXECUTE BLOCK AS\nDECLARE VARIABLE HAS_NULLS INTEGER;\nBEGIN\n" +
+					"  IF (EXISTS(SELECT 1 FROM ORDERS WHERE CODE IS NULL)) THEN\n" +
+					"    HAS_NULLS = CAST('Column ORDERS.CODE has NULL values' AS INTEGER);\nEND;\n",
+				"UPDATE RDB$RELATION_FIELDS SET RDB$NULL_FLAG = 1 WHERE RDB$FIELD_NAME = 'CODE' AND RDB$RELATION_NAME = 'ORDERS';\n",
+				"COMMENT ON COLUMN ORDERS.CODE IS 'short code';\n",
+			},
+		},
+		{
+			name:   "not null without default",
+			target: &TableColumn{Name: "QTY", Type: "INTEGER", Position: 3},
+			source: &TableColumn{Name: "QTY", Type: "INTEGER", NotNull: true, Position: 3},
+			expected: []string{
+				"EXECUTE BLOCK AS\nDECLARE VARIABLE HAS_NULLS INTEGER;\nBEGIN\n" +
+					"  IF (EXISTS(SELECT 1 FROM ORDERS WHERE QTY IS NULL)) THEN\n" +
+					"    HAS_NULLS = CAST('Column ORDERS.QTY has NULL values' AS INTEGER);\nEND;\n",
+				"UPDATE RDB$RELATION_FIELDS SET RDB$NULL_FLAG = 1 WHERE RDB$FIELD_NAME = 'QTY' AND RDB$RELATION_NAME = 'ORDERS';\n",
+			},
+		},
+		{
+			name:   "nullable with dropped default",
+			target: &TableColumn{Name: "QTY", Type: "INTEGER", Default: "DEFAULT 0", NotNull: true, Position: 3},
+			source: &TableColumn{Name: "QTY", Type: "INTEGER", Position: 4},
+			expected: []string{
+				"ALTER TABLE ORDERS ALTER COLUMN QTY DROP DEFAULT;\n",
+				"UPDATE RDB$RELATION_FIELDS SET RDB$NULL_FLAG = NULL WHERE RDB$FIELD_NAME = 'QTY' AND RDB$RELATION_NAME = 'ORDERS';\n",
+				"ALTER TABLE ORDERS ALTER QTY POSITION 4;\n",
+			},
+		},
+		{
+			name:   "computed",
+			target: &TableColumn{Name: "TOTAL", Type: "INTEGER", Position: 5},
+			source: &TableColumn{Name: "TOTAL", ComputedBy: "(QTY * 2)", Position: 5},
+			expected: []string{
+				"ALTER TABLE ORDERS DROP TOTAL;\n",
+				"ALTER TABLE ORDERS ADD TOTAL COMPUTED BY (QTY * 2);\n",
+				"ALTER TABLE ORDERS ALTER TOTAL POSITION 5;\n",
+			},
+		},
+	}
+	for _, test := range tests {
+		sqls := table.AlterColumnDefinition(test.target, test.source, sqlrog.DEFAULT_SQL_SEP)
+		if strings.Join(sqls, "") != strings.Join(test.expected, "") {
+			t.Errorf("Expected %s sql is not equal to real: \n%s\n%s\n", test.name, strings.Join(test.expected, ""), strings.Join(sqls, ""))
+		}
+	}
+}
+
+func TestProcedureParametersSQL(t *testing.T) {
+	procedure := &Procedure{
+		Name:   "FIND_ORDERS",
+		Source: "BEGIN\n  SUSPEND;\nEND",
+		InputParameters: map[string]*ProcedureParameter{
+			"STATUS":   {Name: "STATUS", TypeName: "D_STATUS", TypeOf: true, Position: 2, Default: "= 'new'"},
+			"CUSTOMER": {Name: "CUSTOMER", TypeOfColumn: "CUSTOMERS.ID", NotNull: true, Position: 0},
+			"NOTE":     {Name: "NOTE", TypeName: "VARCHAR(100) CHARACTER SET UTF8", Collate: "UNICODE_CI", Position: 1},
+		},
+		OutputParameters: map[string]*ProcedureParameter{
+			"TOTAL": {Name: "TOTAL", TypeName: "NUMERIC(18,2)", Position: 1},
+			"ID":    {Name: "ID", TypeName: "INTEGER", Position: 0},
+		},
+	}
+	expected := "CREATE PROCEDURE FIND_ORDERS (\n" +
+		"\tCUSTOMER TYPE OF COLUMN CUSTOMERS.ID NOT NULL,\n" +
+		"\tNOTE VARCHAR(100) CHARACTER SET UTF8 COLLATE UNICODE_CI,\n" +
+		"\tSTATUS TYPE OF D_STATUS = 'new') \n" +
+		"returns (\n" +
+		"\tID INTEGER,\n" +
+		"\tTOTAL NUMERIC(18,2))\n" +
+		"as\nBEGIN\n  SUSPEND;\nEND;\n"
+	if sql := procedure.CreateDefinition(sqlrog.DEFAULT_SQL_SEP); sql[0] != expected {
+		t.Errorf("Expected procedure sql is not equal to real: \n%s\n%s\n", expected, sql[0])
+	}
+	other := *procedure
+	other.InputParameters = map[string]*ProcedureParameter{}
+	for name, param := range procedure.InputParameters {
+		copied := *param
+		other.InputParameters[name] = &copied
+	}
+	other.InputParameters["STATUS"].Default = ""
+	if procedure.Equals(&other) {
+		t.Errorf("Expected procedures with different parameter defaults to differ\n")
+	}
+}
+
+func TestProcedureStubsSQL(t *testing.T) {
+	caller := &Procedure{Name: "CALLER", Source: "BEGIN\n  EXECUTE PROCEDURE CALLEE;\nEND"}
+	callee := &Procedure{
+		Name:             "CALLEE",
+		Source:           "BEGIN\n  X = 1;\nEND",
+		OutputParameters: map[string]*ProcedureParameter{"X": {Name: "X", TypeName: "INTEGER"}},
+	}
+	sqls := sortedDiffSql(fbEngine.SchemaDiff(newSchema(caller, callee), newSchema()))
+	if len(sqls) != 4 {
+		t.Fatalf("Expected stubs and bodies of 2 procedures, got: \n%s\n", strings.Join(sqls, "\n"))
+	}
+	for i, prefix := range []string{"CREATE PROCEDURE", "CREATE PROCEDURE", "ALTER PROCEDURE", "ALTER PROCEDURE"} {
+		if !strings.HasPrefix(sqls[i], prefix) {
+			t.Errorf("Expected statement %d to start with %s: \n%s\n", i, prefix, sqls[i])
+		}
+		if i < 2 && !strings.HasSuffix(sqls[i], "as\n"+PROCEDURE_STUB_SOURCE+";\n") {
+			t.Errorf("Expected stub body in statement %d: \n%s\n", i, sqls[i])
+		}
+	}
+	expected := "ALTER PROCEDURE CALLEE \nreturns (\n\tX INTEGER)\nas\nBEGIN\n  X = 1;\nEND;\n"
+	if sqls[2] != expected && sqls[3] != expected {
+		t.Errorf("Expected procedure body is missing: \n%s\n%s\n", expected, strings.Join(sqls, "\n"))
+	}
+}
+
+func TestGeneratorValuesSQL(t *testing.T) {
+	tests := []struct {
+		policy   string
+		source   *Generator
+		target   *Generator
+		expected string
+	}{
+		{sqlrog.GENERATOR_VALUES_EXACT, &Generator{Value: 10}, &Generator{Value: 20}, "ALTER SEQUENCE G_ID RESTART WITH 10;"},
+		{sqlrog.GENERATOR_VALUES_EXACT, &Generator{Value: 20}, &Generator{Value: 20}, ""},
+		{sqlrog.GENERATOR_VALUES_RAISE, &Generator{Value: 10}, &Generator{Value: 20}, ""},
+		{sqlrog.GENERATOR_VALUES_RAISE, &Generator{Value: 30}, &Generator{Value: 20}, "ALTER SEQUENCE G_ID RESTART WITH 30;"},
+		{sqlrog.GENERATOR_VALUES_RAISE, &Generator{Value: 5}, nil, "ALTER SEQUENCE G_ID RESTART WITH 5;"},
+		{sqlrog.GENERATOR_VALUES_RAISE, &Generator{}, nil, ""},
+		{sqlrog.GENERATOR_VALUES_MAX, &Generator{Owner: "ORDERS.ID"}, &Generator{Value: 20, MaxValue: 20}, ""},
+		{sqlrog.GENERATOR_VALUES_MAX, &Generator{}, &Generator{Value: 20, MaxValue: 30}, ""},
+		{sqlrog.GENERATOR_VALUES_MAX, &Generator{Owner: "ORDERS.ID"}, &Generator{Value: 20, MaxValue: 30}, "EXECUTE BLOCK AS\n" +
+			"DECLARE VARIABLE MAX_VALUE BIGINT;\nBEGIN\n" +
+			"  SELECT COALESCE(MAX(ID), 0) FROM ORDERS INTO :MAX_VALUE;\n" +
+			"  IF (MAX_VALUE > GEN_ID(G_ID, 0)) THEN\n" +
+			"    MAX_VALUE = GEN_ID(G_ID, MAX_VALUE - GEN_ID(G_ID, 0));\nEND;"},
+		{"", &Generator{Value: 10}, &Generator{Value: 20}, ""},
+	}
+	for _, test := range tests {
+		test.source.Name, test.source.Policy = "G_ID", test.policy
+		if test.target != nil {
+			test.target.Name, test.target.Policy = "G_ID", test.policy
+		}
+		if sql := test.source.ValueDefinition(test.target, sqlrog.DEFAULT_SQL_SEP); sql != test.expected {
+			t.Errorf("Expected %s generator sql is not equal to real: \n%s\n%s\n", test.policy, test.expected, sql)
+		}
+	}
+}
+
+func TestScriptRenderer(t *testing.T) {
+	procedure := &Procedure{Name: "TOUCH", Source: "BEGIN\n  UPDATE ORDERS SET QTY = QTY;\nEND"}
+	table := &Table{Name: "ORDERS"}
+	column := &TableColumn{Name: "QTY", Type: "INTEGER", NotNull: true}
+
+	renderer := fbEngine.ScriptRenderer()
+	script := renderer.Statements(table.ColumnNullabilityDefinition(column, ""))
+	script += renderer.Statements(procedure.CreateDefinition(""))
+	script += renderer.Statements(procedure.DropDefinition(""))
+	script += renderer.Finish()
+	expectedScript := "SET TERM ^ ;\n" +
+		"EXECUTE BLOCK AS\nDECLARE VARIABLE HAS_NULLS INTEGER;\nBEGIN\n" +
+		"  IF (EXISTS(SELECT 1 FROM ORDERS WHERE QTY IS NULL)) THEN\n" +
+		"    HAS_NULLS = CAST('Column ORDERS.QTY has NULL values' AS INTEGER);\nEND^\n" +
+		"SET TERM ; ^\n" +
+		"UPDATE RDB$RELATION_FIELDS SET RDB$NULL_FLAG = 1 WHERE RDB$FIELD_NAME = 'QTY' AND RDB$RELATION_NAME = 'ORDERS';\n" +
+		"SET TERM ^ ;\n" +
+		"CREATE PROCEDURE TOUCH \nas\nBEGIN\n  UPDATE ORDERS SET QTY = QTY;\nEND^\n" +
+		"SET TERM ; ^\n" +
+		"DROP PROCEDURE TOUCH;\n"
+	if script != expectedScript {
+		t.Errorf("Expected script is not equal to real: \n%s\n%s\n", expectedScript, script)
+	}
+	statements, err := fbEngine.ScriptParser().Split(script)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(statements) != 4 || !strings.HasPrefix(statements[2].SQL, "CREATE PROCEDURE TOUCH") || statements[3].SQL != "DROP PROCEDURE TOUCH" {
+		t.Errorf("Expected rendered script to be parsed back into 4 statements, got %d\n", len(statements))
+	}
+}
+
+func TestTableValidate(t *testing.T) {
+	countries := &Table{
+		Name:   "COUNTRIES",
+		Fields: map[string]*TableColumn{"ID": {Name: "ID", Type: "INTEGER", Position: 0}},
+	}
+	cities := &Table{
+		Name: "CITIES",
+		Fields: map[string]*TableColumn{
+			"ID":         {Name: "ID", Type: "INTEGER", Position: 0},
+			"COUNTRY_ID": {Name: "COUNTRY_ID", Type: "INTEGER", Position: 1},
+			"NAME":       {Name: "TITLE", Domain: "D_NAME", Position: 1},
+		},
+		Indexes: map[string]map[string]*Index{
+			INDEX: {
+				"IDX_CITIES": {Name: "IDX_CITIES", Type: INDEX, TableName: "CITIES", Fields: map[string]IndexField{"CODE": {Name: "CODE"}}},
+			},
+			FOREIGN_KEY: {
+				"FK_CITIES": {Name: "FK_CITIES", Type: FOREIGN_KEY, TableName: "CITIES", SourceTable: "COUNTRIES",
+					Fields:       map[string]IndexField{"COUNTRY_ID": {Name: "COUNTRY_ID"}},
+					SourceFields: map[string]IndexField{"CODE": {Name: "CODE"}}},
+			},
+			CHECK: {
+				"CHK_CITIES": {Name: "CHK_CITIES", Type: CHECK, TableName: "CITIES"},
+			},
+		},
+		Triggers: map[string]*Trigger{
+			"CITIES_BI": {Name: "CITIES_BI", TableName: "COUNTRIES"},
+		},
+	}
+	tables := map[string]sqlrog.ElementSchema{"COUNTRIES": countries, "CITIES": cities}
+
+	expectedMessages := []string{
+		"column 'NAME' has name 'TITLE'",
+		"columns 'COUNTRY_ID' and 'NAME' have the same position 1",
+		"column 'NAME' uses unknown domain 'D_NAME'",
+		"INDEX 'IDX_CITIES' refers to unknown column 'CODE'",
+		"FOREIGN KEY 'FK_CITIES' refers to unknown column 'COUNTRIES.CODE'",
+		"CHECK 'CHK_CITIES' has no expression",
+		"trigger 'CITIES_BI' belongs to table 'COUNTRIES'",
+	}
+	messages := cities.Validate(tables, nil)
+	if len(messages) != len(expectedMessages) {
+		t.Errorf("Expected %d validation messages, got:\n%s\n", len(expectedMessages), strings.Join(messages, "\n"))
+	}
+	for _, expected := range expectedMessages {
+		if !strings.Contains(strings.Join(messages, "\n"), expected) {
+			t.Errorf("Expected validation message is missing: %s\n%s\n", expected, strings.Join(messages, "\n"))
+		}
+	}
+}
+
+func TestFilterCondition(t *testing.T) {
+	filter := &sqlrog.ElementFilter{Match: "car", Ignore: []string{"tmp", "", "log"}}
+	condition, args := FilterCondition(filter, "r.rdb$relation_name")
+
+	expected := " AND r.rdb$relation_name CONTAINING ? AND NOT (r.rdb$relation_name CONTAINING ?) AND NOT (r.rdb$relation_name CONTAINING ?)"
+	if condition != expected {
+		t.Errorf("Unexpected filter condition:\n%s\nexpected:\n%s\n", condition, expected)
+	}
+	if !reflect.DeepEqual(args, []interface{}{"car", "tmp", "log"}) {
+		t.Errorf("Unexpected filter arguments: %v\n", args)
+	}
+	if condition, args := FilterCondition(nil, "r.rdb$relation_name"); condition != "" || len(args) != 0 {
+		t.Errorf("Expected no condition without a filter, got %q %v\n", condition, args)
+	}
+}
+
+func TestLintTableChildren(t *testing.T) {
+	schema := newSchema(&Table{
+		Name:    "CARS",
+		Fields:  map[string]*TableColumn{"ID": {Name: "ID", Type: "INTEGER", NotNull: true}},
+		Indexes: map[string]map[string]*Index{PRIMARY_KEY: {"PK_CARS": {Name: "PK_CARS", Type: PRIMARY_KEY}}},
+		Triggers: map[string]*Trigger{
+			"CARS_BI":     {Name: "CARS_BI", TableName: "CARS"},
+			"TRG_CARS_AU": {Name: "TRG_CARS_AU", TableName: "CARS"},
+		},
+	})
+	config := &sqlrog.LintConfig{Naming: map[string]string{"trigger": "^TRG_", "index": "^PK_"}}
+	issues, err := sqlrog.LintSchema(fbEngine.GetLintRules(), schema, config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var output []string
+	for _, issue := range issues {
+		output = append(output, issue.String())
+	}
+	expected := []string{"[warning] trigger CARS.CARS_BI: name doesn't match '^TRG_' (naming_convention)"}
+	if !reflect.DeepEqual(output, expected) {
+		t.Errorf("Unexpected lint issues:\n%s\nexpected:\n%s\n", strings.Join(output, "\n"), strings.Join(expected, "\n"))
+	}
+}
+
+func TestComputedColumnSQL(t *testing.T) {
+	table := &Table{Name: "ORDERS", Fields: map[string]*TableColumn{
+		"ID":    {Name: "ID", Type: "INTEGER", NotNull: true, Position: 0},
+		"PRICE": {Name: "PRICE", Type: "NUMERIC(15,2)", Position: 1},
+		"TOTAL": {Name: "TOTAL", Type: "NUMERIC(18,2)", ComputedBy: "(PRICE * 2)", Position: 2},
+	}}
+	if sql := table.CreateDefinition(sqlrog.DEFAULT_SQL_SEP)[0]; !strings.Contains(sql, "TOTAL COMPUTED BY (PRICE * 2)") {
+		t.Errorf("Expected computed column in table sql:\n%s\n", sql)
+	}
+	if columns := table.DataColumns(); !reflect.DeepEqual(columns, []string{"ID", "PRICE"}) {
+		t.Errorf("Expected computed columns to be left out of data, got %v\n", columns)
+	}
+
+	cases := []struct {
+		name     string
+		target   *TableColumn
+		source   *TableColumn
+		expected []string
+	}{
+		{
+			name:   "expression change",
+			target: &TableColumn{Name: "TOTAL", Type: "NUMERIC(18,2)", ComputedBy: "(PRICE * 2)", Position: 2},
+			source: &TableColumn{Name: "TOTAL", Type: "NUMERIC(18,2)", ComputedBy: "(PRICE * 3)", Position: 2},
+			expected: []string{
+				"ALTER TABLE ORDERS ALTER TOTAL COMPUTED BY (PRICE * 3);\n",
+			},
+		},
+		{
+			name:   "regular column becomes computed",
+			target: &TableColumn{Name: "TOTAL", Type: "NUMERIC(18,2)", Position: 2},
+			source: &TableColumn{Name: "TOTAL", Type: "NUMERIC(18,2)", ComputedBy: "(PRICE * 2)", Position: 2},
+			expected: []string{
+				"ALTER TABLE ORDERS DROP TOTAL;\n",
+				"ALTER TABLE ORDERS ADD TOTAL COMPUTED BY (PRICE * 2);\n",
+				"ALTER TABLE ORDERS ALTER TOTAL POSITION 2;\n",
+			},
+		},
+	}
+	for _, c := range cases {
+		sqls := table.AlterColumnDefinition(c.target, c.source, sqlrog.DEFAULT_SQL_SEP)
+		if !reflect.DeepEqual(sqls, c.expected) {
+			t.Errorf("%s: unexpected sql:\n%s\nexpected:\n%s\n", c.name, strings.Join(sqls, ""), strings.Join(c.expected, ""))
+		}
+	}
+}
+
+func TestCheckConstraintSQL(t *testing.T) {
+	check := &Index{Name: "CHK_PRICE", Type: CHECK, TableName: "ORDERS", Expression: "PRICE > 0", Active: true}
+	if sql := check.CreateDefinition(sqlrog.DEFAULT_SQL_SEP); !reflect.DeepEqual(sql, []string{"ALTER TABLE ORDERS ADD CONSTRAINT CHK_PRICE CHECK (PRICE > 0);"}) {
+		t.Errorf("Unexpected check sql: %v\n", sql)
+	}
+	if sql := check.DropDefinition(sqlrog.DEFAULT_SQL_SEP); !reflect.DeepEqual(sql, []string{"ALTER TABLE ORDERS DROP CONSTRAINT CHK_PRICE;"}) {
+		t.Errorf("Unexpected check drop sql: %v\n", sql)
+	}
+	index := &Index{Name: "IDX_PRICE", Type: INDEX, TableName: "ORDERS", Active: true}
+	if sql := index.DropDefinition(sqlrog.DEFAULT_SQL_SEP); !reflect.DeepEqual(sql, []string{"DROP INDEX IDX_PRICE;"}) {
+		t.Errorf("Unexpected index drop sql: %v\n", sql)
+	}
+	for source, expected := range map[string]string{
+		"CHECK (PRICE > 0)":    "(PRICE > 0)",
+		"  check(PRICE > 0)\n": "(PRICE > 0)",
+		"(CHECKED = 1)":        "(CHECKED = 1)",
+	} {
+		if expression := CheckExpression(source); expression != expected {
+			t.Errorf("CheckExpression(%q) = %q, expected %q\n", source, expression, expected)
+		}
+	}
+}
+
+func TestFetchChecksFromDB(t *testing.T) {
+	conn, db := openFakeDB(t, &fakeResult{
+		Match:   "rdb$check_constraints",
+		Columns: []string{"RELATION", "NAME", "SOURCE"},
+		Rows:    [][]driver.Value{{"ORDERS", "CHK_PRICE", "check (PRICE > 0)"}},
+	})
+	defer conn.Close()
+
+	checks, err := (&Index{}).FetchChecksFromDB(context.Background(), conn, &sqlrog.ElementFilter{Match: "ORD"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(checks) != 1 || checks[0].TableName != "ORDERS" || checks[0].Name != "CHK_PRICE" || checks[0].Type != CHECK || checks[0].Expression != "(PRICE > 0)" {
+		t.Errorf("Unexpected checks: %+v\n", checks)
+	}
+	query := db.queries[0]
+	for _, expected := range []string{"rdb$check_constraints", "rdb$trigger_type = 1", "rc.rdb$constraint_type = 'CHECK'", "rc.rdb$relation_name CONTAINING ?"} {
+		if !strings.Contains(query, expected) {
+			t.Errorf("Expected check query to contain %q:\n%s\n", expected, query)
+		}
+	}
+	if !reflect.DeepEqual(db.args[0], []driver.Value{"ORD"}) {
+		t.Errorf("Unexpected check query arguments: %v\n", db.args[0])
+	}
+}
+
+func TestDatabaseTriggerSQL(t *testing.T) {
+	trigger := &Trigger{Name: "TRG_CONNECT", TypeName: "on connect", Position: 0, Active: true, Source: "AS\nBEGIN\nEND"}
+	changed := &Trigger{Name: "TRG_CONNECT", TypeName: "on connect", Position: 0, Active: false, Source: "AS\nBEGIN\nEND"}
+	cases := []struct {
+		name     string
+		source   *FbSchema
+		target   *FbSchema
+		expected []string
+	}{
+		{
+			name:     "create",
+			source:   newSchema(trigger),
+			target:   newSchema(),
+			expected: []string{"CREATE TRIGGER TRG_CONNECT\nACTIVE on connect POSITION 0\nAS\nBEGIN\nEND;"},
+		},
+		{
+			name:     "alter",
+			source:   newSchema(changed),
+			target:   newSchema(trigger),
+			expected: []string{"CREATE OR ALTER TRIGGER TRG_CONNECT\nINACTIVE on connect POSITION 0\nAS\nBEGIN\nEND;"},
+		},
+		{
+			name:     "drop",
+			source:   newSchema(),
+			target:   newSchema(trigger),
+			expected: []string{"DROP TRIGGER TRG_CONNECT;"},
+		},
+	}
+	for _, c := range cases {
+		sqls := sortedDiffSql(fbEngine.SchemaDiff(c.source, c.target))
+		if !reflect.DeepEqual(sqls, c.expected) {
+			t.Errorf("%s: unexpected sql:\n%s\nexpected:\n%s\n", c.name, strings.Join(sqls, "\n"), strings.Join(c.expected, "\n"))
+		}
+	}
+}
+
+func TestFetchTriggersFromDB(t *testing.T) {
+	conn, db := openFakeDB(t,
+		&fakeResult{
+			Match:   "RDB$RELATION_NAME is not null",
+			Columns: []string{"RELATION", "NAME", "ACTIVE", "TYPE", "POSITION", "SOURCE"},
+			Rows:    [][]driver.Value{{"ORDERS", "TRG_ORDERS_BI", int64(1), "before insert", int64(0), "AS BEGIN END"}},
+		},
+		&fakeResult{
+			Match:   "RDB$RELATION_NAME is null",
+			Columns: []string{"NAME", "ACTIVE", "TYPE", "POSITION", "SOURCE"},
+			Rows:    [][]driver.Value{{"TRG_CONNECT", int64(0), "on connect", int64(0), "AS BEGIN END"}},
+		},
+	)
+	defer conn.Close()
+
+	tableTriggers, err := (&Trigger{}).FetchTriggersFromDB(context.Background(), conn, &sqlrog.ElementFilter{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if trigger := tableTriggers["ORDERS"]["TRG_ORDERS_BI"]; trigger == nil || !trigger.Active || trigger.TypeName != "before insert" {
+		t.Errorf("Unexpected table triggers: %+v\n", tableTriggers)
+	}
+	for _, expected := range []string{"RDB$RELATION_NAME is not null", "not exists (select 1 from RDB$CHECK_CONSTRAINTS cc"} {
+		if !strings.Contains(db.queries[0], expected) {
+			t.Errorf("Expected table trigger query to contain %q:\n%s\n", expected, db.queries[0])
+		}
+	}
+
+	databaseTriggers, err := (&Trigger{}).FetchElementsFromDB(context.Background(), conn, &sqlrog.ElementFilter{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(databaseTriggers) != 1 {
+		t.Fatalf("Expected one database trigger, got %d\n", len(databaseTriggers))
+	}
+	if trigger := databaseTriggers[0].(*Trigger); trigger.Name != "TRG_CONNECT" || trigger.TableName != "" || trigger.Active {
+		t.Errorf("Unexpected database trigger: %+v\n", trigger)
+	}
+	if !strings.Contains(db.queries[1], "RDB$RELATION_NAME is null") {
+		t.Errorf("Expected database trigger query to skip table triggers:\n%s\n", db.queries[1])
+	}
+}
+
+func TestIndexAlterSQL(t *testing.T) {
+	fields := map[string]IndexField{"PRICE": {Name: "PRICE", Position: 0}}
+	index := &Index{Name: "IDX_PRICE", Type: INDEX, TableName: "ORDERS", Fields: fields, Asc: true, Active: true}
+	cases := []struct {
+		name     string
+		source   *Index
+		target   *Index
+		expected []string
+	}{
+		{
+			name:     "deactivate",
+			source:   &Index{Name: "IDX_PRICE", Type: INDEX, TableName: "ORDERS", Fields: fields, Asc: true},
+			target:   index,
+			expected: []string{"ALTER INDEX IDX_PRICE INACTIVE;"},
+		},
+		{
+			name:     "comment",
+			source:   &Index{Name: "IDX_PRICE", Type: INDEX, TableName: "ORDERS", Fields: fields, Asc: true, Active: true, Comment: "Price lookup"},
+			target:   index,
+			expected: []string{"COMMENT ON INDEX IDX_PRICE IS 'Price lookup';"},
+		},
+		{
+			name:   "structure",
+			source: &Index{Name: "IDX_PRICE", Type: INDEX, TableName: "ORDERS", Fields: fields, Asc: false, Active: false},
+			target: index,
+			expected: []string{
+				"DROP INDEX IDX_PRICE;",
+				"CREATE DESCENDING INDEX IDX_PRICE ON ORDERS (PRICE);",
+				"ALTER INDEX IDX_PRICE INACTIVE;",
+			},
+		},
+	}
+	for _, c := range cases {
+		sqls := c.source.AlterDefinition(c.target, sqlrog.DEFAULT_SQL_SEP)
+		if !reflect.DeepEqual(sqls, c.expected) {
+			t.Errorf("%s: unexpected sql:\n%s\nexpected:\n%s\n", c.name, strings.Join(sqls, "\n"), strings.Join(c.expected, "\n"))
+		}
+	}
+
+	unique := &Index{Name: "UQ_PRICE", Type: UNIQUE, TableName: "ORDERS", Fields: fields, Asc: true, Active: true}
+	inactiveUnique := &Index{Name: "UQ_PRICE", Type: UNIQUE, TableName: "ORDERS", Fields: fields, Asc: true}
+	if diff := inactiveUnique.Diff(unique); diff != nil {
+		t.Errorf("Expected activity of a constraint to be ignored, got %v\n", diff.DiffSql(sqlrog.DEFAULT_SQL_SEP))
+	}
+	if sqls := inactiveUnique.CreateDefinition(sqlrog.DEFAULT_SQL_SEP); !reflect.DeepEqual(sqls, []string{"ALTER TABLE ORDERS ADD CONSTRAINT UQ_PRICE UNIQUE (PRICE);"}) {
+		t.Errorf("Expected constraint to be created without ALTER INDEX, got %v\n", sqls)
+	}
+}
+
+func TestStatisticsDiff(t *testing.T) {
+	table := func(data sqlrog.DataRows) *Table {
+		fields := map[string]IndexField{"ID": {Name: "ID", Position: 0}}
+		return &Table{
+			Name:   "COLORS",
+			Fields: map[string]*TableColumn{"ID": {Name: "ID", Type: "INTEGER", NotNull: true}},
+			Indexes: map[string]map[string]*Index{
+				PRIMARY_KEY: {"PK_COLORS": {Name: "PK_COLORS", Type: PRIMARY_KEY, TableName: "COLORS", Fields: fields, Asc: true, Active: true}},
+				INDEX: {
+					"IDX_ACTIVE":   {Name: "IDX_ACTIVE", Type: INDEX, TableName: "COLORS", Fields: fields, Asc: true, Active: true},
+					"IDX_INACTIVE": {Name: "IDX_INACTIVE", Type: INDEX, TableName: "COLORS", Fields: fields, Asc: true},
+				},
+				CHECK: {"CHK_ID": {Name: "CHK_ID", Type: CHECK, TableName: "COLORS", Expression: "(ID > 0)", Active: true}},
+			},
+			Data: data,
+		}
+	}
+	red, blue := "red", "blue"
+	statistics := []string{"SET STATISTICS INDEX IDX_ACTIVE;", "SET STATISTICS INDEX PK_COLORS;"}
+	cases := []struct {
+		name       string
+		source     sqlrog.DataRows
+		target     sqlrog.DataRows
+		statistics bool
+		expected   []string
+	}{
+		{
+			name:       "changed data",
+			source:     sqlrog.DataRows{"1": {"ID": &red}},
+			target:     sqlrog.DataRows{"1": {"ID": &blue}},
+			statistics: true,
+			expected:   statistics,
+		},
+		{
+			name:       "statistics are off",
+			source:     sqlrog.DataRows{"1": {"ID": &red}},
+			target:     sqlrog.DataRows{"1": {"ID": &blue}},
+			statistics: false,
+		},
+		{
+			name:       "same data",
+			source:     sqlrog.DataRows{"1": {"ID": &red}},
+			target:     sqlrog.DataRows{"1": {"ID": &red}},
+			statistics: true,
+		},
+	}
+	for _, c := range cases {
+		target := newSchema(table(c.target))
+		target.Statistics = c.statistics
+		var sqls []string
+		for _, diff := range fbEngine.SchemaDiff(newSchema(table(c.source)), target) {
+			if diff.Type == CORE_ELEMENT_INDEX_STATISTICS_NAME {
+				sqls = append(sqls, diff.DiffSql(sqlrog.DEFAULT_SQL_SEP)...)
+			}
+		}
+		if !reflect.DeepEqual(sqls, c.expected) {
+			t.Errorf("%s: unexpected statistics sql: %v, expected %v\n", c.name, sqls, c.expected)
+		}
+	}
+}
+
+func TestProcedureDropOrder(t *testing.T) {
+	procedure := &Procedure{Name: "ORDER_TOTAL", Source: "BEGIN\n  SUSPEND;\nEND"}
+	view := &View{Name: "ORDER_TOTALS", Source: "SELECT TOTAL FROM ORDER_TOTAL"}
+	trigger := &Trigger{Name: "ORDERS_BU", TableName: "ORDERS", TypeName: "before update", Source: "AS BEGIN EXECUTE PROCEDURE ORDER_TOTAL; END", Active: true}
+	grant := &Grant{Grantee: "CLERK", Privileges: []*sqlrog.Privilege{{Privilege: "EXECUTE", ObjectType: GRANT_OBJECT_PROCEDURE, Object: "ORDER_TOTAL"}}}
+
+	sqls := sortedDiffSql(fbEngine.SchemaDiff(newSchema(), newSchema(procedure, view, trigger, grant)))
+	expected := []string{
+		"DROP VIEW ORDER_TOTALS;",
+		"DROP TRIGGER ORDERS_BU;",
+		"REVOKE EXECUTE ON PROCEDURE ORDER_TOTAL FROM CLERK;",
+		"DROP PROCEDURE ORDER_TOTAL;",
+	}
+	if !reflect.DeepEqual(sqls, expected) {
+		t.Errorf("Unexpected drop order:\n%s\nexpected:\n%s\n", strings.Join(sqls, "\n"), strings.Join(expected, "\n"))
+	}
+}
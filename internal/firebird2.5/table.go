@@ -2,9 +2,11 @@ package fb
 
 import (
 	"bytes"
+	"context"
 	"database/sql"
 	"fmt"
 	"github.com/stpatrickw/sqlrog/internal/sqlrog"
+	"sort"
 	"text/template"
 )
 
@@ -20,6 +22,7 @@ type Table struct {
 	Fields                   map[string]*TableColumn      `yaml:"columns"`
 	Indexes                  map[string]map[string]*Index `yaml:"indexes"`
 	Triggers                 map[string]*Trigger          `yaml:"triggers"`
+	Data                     sqlrog.DataRows              `yaml:"data,omitempty"`
 }
 
 func (t *Table) GetName() string {
@@ -54,9 +57,6 @@ func (t *Table) AlterDefinition(t2 interface{}, sep string) []string {
 			diffs = append(diffs, fb.CompareScheme(t.Indexes[indexType], other.Indexes[indexType])...)
 		}
 	}
-	if !fb.Equals(t.Triggers, other.Triggers) {
-		diffs = append(diffs, fb.CompareScheme(t.Triggers, other.Triggers)...)
-	}
 	for _, diff := range diffs {
 		definitions = append(definitions, diff.DiffSql(sep)...)
 	}
@@ -69,13 +69,13 @@ func (t *Table) CreateDefinition(sep string) []string {
 }
 
 func (t *Table) DropDefinition(sep string) []string {
-	return []string{fmt.Sprintf("DROP TABLE %s%s", t.Name, sep)}
+	return []string{fmt.Sprintf("DROP TABLE %s%s", QuoteIdentifier(t.Name), sep)}
 }
 
 func (t *Table) Definition() string {
-	tableTmpl, err := template.New("table").Parse(`TABLE {{ .Name }} (
+	tableTmpl, err := template.New("table").Funcs(templateFuncs).Parse(`TABLE {{ quote .Name }} (
 	{{$first := true}}{{range .Fields }}{{if $first}}{{$first = false}}{{else}},
-	{{end}}{{ .Name }} {{if ne .Domain "" }}{{ .Domain }}{{ else }}{{ .Type }}{{end}}{{if ne .Charset "" }} CHARACTER SET {{ .Charset }}{{end}}{{if ne .Default "" }} {{ .Default }}{{end}}{{if .NotNull }} NOT NULL{{end}}{{if ne .Collate "" }} COLLATE {{ .Collate }}{{end}}{{end}}
+	{{end}}{{ quote .Name }} {{ .Definition }}{{end}}
 )`)
 
 	if err != nil {
@@ -96,69 +96,21 @@ func (t *Table) DiffColumnDefinition(diff *sqlrog.DiffObject, sep string) []stri
 	switch diff.State {
 	case sqlrog.DIFF_TYPE_CREATE:
 		column := diff.To.(*TableColumn)
-		definition := fmt.Sprintf("ALTER TABLE %s ADD %s", t.Name, column.Name)
-		if column.Domain != "" {
-			definition += " " + column.Domain
-		} else {
-			definition += " " + column.Type
-			if column.Charset != "" {
-				definition += " CHARACTER SET " + column.Charset
-			}
-			if column.Collate != "" {
-				definition += " COLLATE " + column.Collate
-			}
-		}
-		if column.NotNull {
-			definition += " NOT NULL"
-		}
-		if column.Default != "" {
-			definition += " DEFAULT " + column.Default
-		}
-		definitions = append(definitions, definition+sep+"\n")
+		definitions = append(definitions, fmt.Sprintf("ALTER TABLE %s ADD %s %s%s\n", QuoteIdentifier(t.Name), QuoteIdentifier(column.Name), column.Definition(), sep))
 		if column.Comment != "" {
 			definitions = append(definitions, t.CommentOnColumn(column, sep))
 		}
 	case sqlrog.DIFF_TYPE_DROP:
 		column := diff.From.(*TableColumn)
-		definitions = append(definitions, fmt.Sprintf("ALTER TABLE %s DROP %s%s\n", t.Name, column.Name, sep))
+		definitions = append(definitions, fmt.Sprintf("ALTER TABLE %s DROP %s%s\n", QuoteIdentifier(t.Name), QuoteIdentifier(column.Name), sep))
 	case sqlrog.DIFF_TYPE_UPDATE:
-		columnFrom := diff.To.(*TableColumn)
-		columnTo := diff.From.(*TableColumn)
-		if columnTo.NotNull != columnFrom.NotNull {
-			notnull := "NULL"
-			if columnTo.NotNull {
-				notnull = "1"
-			}
-			definitions = append(definitions, fmt.Sprintf("UPDATE RDB$RELATION_FIELDS SET RDB$NULL_FLAG = %s WHERE RDB$FIELD_NAME = '%s' AND RDB$RELATION_NAME = '%s'%s\n", notnull, columnTo.Name, t.Name, sep))
-		}
-		if columnFrom.Domain != columnTo.Domain {
-			if columnTo.Domain != "" {
-				definitions = append(definitions, fmt.Sprintf("UPDATE RDB$RELATION_FIELDS SET RDB$FIELD_SOURCE = '%s' WHERE RDB$FIELD_NAME = '%s' AND RDB$RELATION_NAME = '%s'%s\n", columnTo.Domain, columnTo.Name, t.Name, sep))
-			}
-		} else if columnFrom.Type != columnTo.Type {
-			definitions = append(definitions, fmt.Sprintf("ALTER TABLE %s ALTER COLUMN %s TYPE %s%s\n", t.Name, columnTo.Name, columnTo.Type, sep))
-		}
-		if columnFrom.Default != columnTo.Default {
-			definitions = append(definitions, fmt.Sprintf("ALTER TABLE %s ALTER COLUMN %s SET %s%s\n", t.Name, columnTo.Name, columnTo.Default, sep))
-		}
-		if columnFrom.Charset != columnTo.Charset {
-			definitions = append(definitions, fmt.Sprintf("UPDATE RDB$FIELDS SET RDB$CHARACTER_SET_ID = (SELECT FIRST 1 RDB$CHARACTER_SET_ID FROM RDB$COLLATIONS WHERE RDB$COLLATION_NAME = '%s') WHERE RDB$FIELD_NAME = '%s'%s\n", columnTo.Charset, columnFrom.FieldSource, sep))
-		}
-		if columnFrom.Collate != columnTo.Collate {
-			definitions = append(definitions, fmt.Sprintf("UPDATE RDB$RELATION_FIELDS SET RDB$COLLATION_ID = (SELECT FIRST 1 RDB$COLLATION_ID FROM RDB$COLLATIONS WHERE RDB$COLLATION_NAME = '%s') WHERE RDB$FIELD_NAME = '%s' AND RDB$RELATION_NAME = '%s'%s\n", columnTo.Collate, columnFrom.Name, t.Name, sep))
-		}
-		if columnFrom.Comment != columnTo.Comment {
-			definitions = append(definitions, t.CommentOnColumn(columnTo, sep))
-		}
-		if columnFrom.Position != columnTo.Position {
-			definitions = append(definitions, fmt.Sprintf("ALTER TABLE %s ALTER %s POSITION %d%s\n", t.Name, columnTo.Name, columnTo.Position, sep))
-		}
+		definitions = append(definitions, t.AlterColumnDefinition(diff.To.(*TableColumn), diff.From.(*TableColumn), sep)...)
 	}
 	return definitions
 }
 
 func (t *Table) CommentOnColumn(column *TableColumn, sep string) string {
-	return fmt.Sprintf("COMMENT ON COLUMN %s.%s IS '%s'%s\n", t.Name, column.Name, column.Comment, sep)
+	return fmt.Sprintf("COMMENT ON COLUMN %s.%s IS %s%s\n", QuoteIdentifier(t.Name), QuoteIdentifier(column.Name), StringLiteral(column.Comment), sep)
 }
 
 func (t *Table) Equals(t2 interface{}) bool {
@@ -179,6 +131,10 @@ func (t *Table) Equals(t2 interface{}) bool {
 		return false
 	}
 
+	if !sqlrog.DataRowsEqual(t.Data, other.Data) {
+		return false
+	}
+
 	return true
 }
 
@@ -209,10 +165,33 @@ func (t *Table) DiffsOnCreate(schema sqlrog.ElementSchema) []*sqlrog.DiffObject
 	})
 
 	fb := &FirebirdEngine{}
+	var childDiffs []*sqlrog.DiffObject
 	for _, indexesByType := range t.Indexes {
-		diffs = append(diffs, fb.CompareScheme(indexesByType, nil)...)
+		childDiffs = append(childDiffs, fb.CompareScheme(indexesByType, nil)...)
+	}
+	childDiffs = append(childDiffs, fb.CompareScheme(t.Triggers, nil)...)
+	// indexes and triggers are saved in the table file
+	for _, diff := range childDiffs {
+		diff.SqlOnly = true
+	}
+
+	return append(diffs, childDiffs...)
+}
+
+// TableTriggersDiff compares triggers of tables existing in both schemas. They are not a part of the
+// table alter, so they are applied after procedures and views they could use.
+func (fb *FirebirdEngine) TableTriggersDiff(source *FbSchema, target *FbSchema) []*sqlrog.DiffObject {
+	var diffs []*sqlrog.DiffObject
+	for name, element := range source.CoreElements[CORE_ELEMENT_TABLE_NAME] {
+		targetElement, ok := target.CoreElements[CORE_ELEMENT_TABLE_NAME][name]
+		if !ok {
+			continue
+		}
+		for _, diff := range fb.CompareScheme(element.(*Table).Triggers, targetElement.(*Table).Triggers) {
+			diff.SqlOnly = true
+			diffs = append(diffs, diff)
+		}
 	}
-	diffs = append(diffs, fb.CompareScheme(t.Triggers, nil)...)
 
 	return diffs
 }
@@ -225,14 +204,15 @@ func (t *Table) CastType(other interface{}) *Table {
 	return other.(*Table)
 }
 
-func (t *Table) FetchElementsFromDB(conn *sql.DB) ([]sqlrog.ElementSchema, error) {
+func (t *Table) FetchElementsFromDB(ctx context.Context, conn *sql.DB, filter *sqlrog.ElementFilter) ([]sqlrog.ElementSchema, error) {
 	tablesMap := make(map[string]*Table)
-	rows, err := conn.Query(`
+	condition, args := FilterCondition(filter, "rdb$relation_name")
+	rows, err := conn.QueryContext(ctx, `
 		select trim(rdb$relation_name) 
 		from rdb$relations
 		where rdb$view_blr is null and 
-		      (rdb$system_flag is null or rdb$system_flag = 0)
-		order by rdb$relation_name`)
+		      (rdb$system_flag is null or rdb$system_flag = 0)`+condition+`
+		order by rdb$relation_name`, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -246,7 +226,7 @@ func (t *Table) FetchElementsFromDB(conn *sql.DB) ([]sqlrog.ElementSchema, error
 		tablesMap[table.Name] = table
 	}
 	tableFieldEntity := &TableColumn{}
-	tableFields, err := tableFieldEntity.FetchColumnsFromDB(conn)
+	tableFields, err := tableFieldEntity.FetchColumnsFromDB(ctx, conn, filter)
 	if err != nil {
 		return nil, err
 	}
@@ -257,7 +237,7 @@ func (t *Table) FetchElementsFromDB(conn *sql.DB) ([]sqlrog.ElementSchema, error
 		tablesMap[tableName].Fields = fieldsByTable
 	}
 	triggerEntity := &Trigger{}
-	triggers, err := triggerEntity.FetchTriggersFromDB(conn)
+	triggers, err := triggerEntity.FetchTriggersFromDB(ctx, conn, filter)
 	if err != nil {
 		return nil, err
 	}
@@ -268,7 +248,7 @@ func (t *Table) FetchElementsFromDB(conn *sql.DB) ([]sqlrog.ElementSchema, error
 		tablesMap[tableName].Triggers = triggersByTable
 	}
 	indexEntity := &Index{}
-	indexes, err := indexEntity.FetchIndexesFromDB(conn)
+	indexes, err := indexEntity.FetchIndexesFromDB(ctx, conn, filter)
 	if err != nil {
 		return nil, err
 	}
@@ -285,3 +265,15 @@ func (t *Table) FetchElementsFromDB(conn *sql.DB) ([]sqlrog.ElementSchema, error
 
 	return tables, nil
 }
+
+func OrderedColumnFields(fields map[string]*TableColumn) []*TableColumn {
+	var columnFields []*TableColumn
+	for _, columnField := range fields {
+		columnFields = append(columnFields, columnField)
+	}
+	sort.Slice(columnFields, func(i, j int) bool {
+		return columnFields[i].Position < columnFields[j].Position
+	})
+
+	return columnFields
+}
@@ -1,8 +1,10 @@
 package fb
 
 import (
+	"context"
 	"database/sql"
 	"github.com/stpatrickw/sqlrog/internal/sqlrog"
+	"strings"
 )
 
 type TableColumn struct {
@@ -17,6 +19,7 @@ type TableColumn struct {
 	Default                  string
 	Comment                  string
 	Position                 int
+	ComputedBy               string `yaml:"computedby,omitempty"`
 }
 
 func (t *TableColumn) Equals(t2 interface{}) bool {
@@ -24,7 +27,66 @@ func (t *TableColumn) Equals(t2 interface{}) bool {
 
 	return t.Type == other.Type && t.Domain == other.Domain && t.NotNull == other.NotNull &&
 		t.Charset == other.Charset && t.Collate == other.Collate && t.Default == other.Default &&
-		t.Comment == other.Comment && t.Position == other.Position
+		t.Comment == other.Comment && t.Position == other.Position && t.ComputedBy == other.ComputedBy
+}
+
+// Definition returns the column definition without the name.
+func (f *TableColumn) Definition() string {
+	if f.ComputedBy != "" {
+		return f.ComputedDefinition()
+	}
+	definition := f.TypeDefinition()
+	if f.Default != "" {
+		definition += " " + f.Default
+	}
+	if f.NotNull {
+		definition += " NOT NULL"
+	}
+	if f.Collate != "" {
+		definition += " COLLATE " + f.Collate
+	}
+
+	return definition
+}
+
+// TypeDefinition returns the domain or the data type with the character set.
+func (f *TableColumn) TypeDefinition() string {
+	if f.Domain != "" {
+		return QuoteIdentifier(f.Domain)
+	}
+	if f.Charset != "" {
+		return f.Type + " CHARACTER SET " + f.Charset
+	}
+
+	return f.Type
+}
+
+func (f *TableColumn) ComputedDefinition() string {
+	return "COMPUTED BY " + ParenthesizedExpression(f.ComputedBy)
+}
+
+// ParenthesizedExpression wraps the expression into parentheses unless it's wrapped already.
+func ParenthesizedExpression(expression string) string {
+	expression = strings.TrimSpace(expression)
+	if strings.HasPrefix(expression, "(") && strings.HasSuffix(expression, ")") {
+		depth := 0
+		for i, char := range expression {
+			switch char {
+			case '(':
+				depth++
+			case ')':
+				depth--
+			}
+			if depth == 0 {
+				if i == len(expression)-1 {
+					return expression
+				}
+				break
+			}
+		}
+	}
+
+	return "(" + expression + ")"
 }
 
 func (f *TableColumn) Diff(t2 interface{}) *sqlrog.DiffObject {
@@ -50,10 +112,12 @@ func (f *TableColumn) GetTypeName() string {
 	return "table_column"
 }
 
-func (f *TableColumn) FetchColumnsFromDB(conn *sql.DB) (map[string]map[string]*TableColumn, error) {
+func (f *TableColumn) FetchColumnsFromDB(ctx context.Context, conn *sql.DB, filter *sqlrog.ElementFilter) (map[string]map[string]*TableColumn, error) {
 	fields := make(map[string]map[string]*TableColumn)
 
-	fieldRows, err := conn.Query(`
+	condition, args := FilterCondition(filter, "RF.RDB$RELATION_NAME")
+
+	fieldRows, err := conn.QueryContext(ctx, `
 			SELECT
                TRIM(RF.RDB$RELATION_NAME),
               TRIM(RF.RDB$FIELD_NAME) FIELD_NAME, TRIM(RF.RDB$FIELD_SOURCE),
@@ -96,14 +160,15 @@ func (f *TableColumn) FetchColumnsFromDB(conn *sql.DB) (map[string]map[string]*T
               TRIM(COALESCE(RF.RDB$DEFAULT_SOURCE, F.RDB$DEFAULT_SOURCE, '')) FIELD_DEFAULT,
             --  F.RDB$VALIDATION_SOURCE FIELD_CHECK,
               TRIM(COALESCE(RF.RDB$DESCRIPTION, '')) FIELD_DESCRIPTION,
-              RF.RDB$FIELD_POSITION +1 
+              RF.RDB$FIELD_POSITION +1,
+              TRIM(COALESCE(F.RDB$COMPUTED_SOURCE, '')) FIELD_COMPUTED
             FROM RDB$RELATION_FIELDS RF
             JOIN RDB$RELATIONS R ON R.RDB$RELATION_NAME = RF.RDB$RELATION_NAME
             JOIN RDB$FIELDS F ON (F.RDB$FIELD_NAME = RF.RDB$FIELD_SOURCE)
             LEFT OUTER JOIN RDB$CHARACTER_SETS CH ON (CH.RDB$CHARACTER_SET_ID = F.RDB$CHARACTER_SET_ID)
             LEFT OUTER JOIN RDB$COLLATIONS DCO ON ((DCO.RDB$COLLATION_ID = RF.RDB$COLLATION_ID) AND (DCO.RDB$CHARACTER_SET_ID = F.RDB$CHARACTER_SET_ID))
-            WHERE COALESCE(RF.RDB$SYSTEM_FLAG, 0) = 0 AND R.rdb$view_blr is null
-            ORDER BY RF.RDB$FIELD_POSITION`)
+            WHERE COALESCE(RF.RDB$SYSTEM_FLAG, 0) = 0 AND R.rdb$view_blr is null`+condition+`
+            ORDER BY RF.RDB$FIELD_POSITION`, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -112,7 +177,7 @@ func (f *TableColumn) FetchColumnsFromDB(conn *sql.DB) (map[string]map[string]*T
 	for fieldRows.Next() {
 		field := &TableColumn{}
 		var relationName string
-		err := fieldRows.Scan(&relationName, &field.Name, &field.FieldSource, &field.Type, &field.Domain, &field.NotNull, &field.Charset, &field.Collate, &field.Default, &field.Comment, &field.Position)
+		err := fieldRows.Scan(&relationName, &field.Name, &field.FieldSource, &field.Type, &field.Domain, &field.NotNull, &field.Charset, &field.Collate, &field.Default, &field.Comment, &field.Position, &field.ComputedBy)
 		if err != nil {
 			return nil, err
 		}
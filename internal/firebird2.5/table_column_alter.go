@@ -0,0 +1,204 @@
+package fb
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+const REBUILD_COLUMN_NAME = "SQLROG$REBUILD"
+
+var (
+	columnTypeRegexp = regexp.MustCompile(`^([A-Z ]+?)\s*(?:\(\s*(\d+)\s*(?:,\s*(\d+)\s*)?\))?$`)
+	integerDigits    = map[string]int{"SMALLINT": 5, "INTEGER": 10, "BIGINT": 19}
+	temporalLengths  = map[string]int{"DATE": 11, "TIME": 13, "TIMESTAMP": 24}
+)
+
+// AlterColumnDefinition turns the target column into the source one.
+func (t *Table) AlterColumnDefinition(target *TableColumn, source *TableColumn, sep string) []string {
+	var definitions []string
+	if (target.ComputedBy == "") != (source.ComputedBy == "") {
+		// a regular column can't become computed and vice versa, so it's recreated
+		definitions = append(definitions, fmt.Sprintf("ALTER TABLE %s DROP %s%s\n", QuoteIdentifier(t.Name), QuoteIdentifier(target.Name), sep))
+		definitions = append(definitions, fmt.Sprintf("ALTER TABLE %s ADD %s %s%s\n", QuoteIdentifier(t.Name), QuoteIdentifier(source.Name), source.Definition(), sep))
+		if source.Comment != "" {
+			definitions = append(definitions, t.CommentOnColumn(source, sep))
+		}
+		return append(definitions, t.ColumnPositionDefinition(source, sep))
+	}
+	if source.ComputedBy != "" {
+		if target.ComputedBy != source.ComputedBy {
+			definitions = append(definitions, fmt.Sprintf("ALTER TABLE %s ALTER %s %s%s\n", QuoteIdentifier(t.Name), QuoteIdentifier(source.Name), source.ComputedDefinition(), sep))
+		}
+		if target.Comment != source.Comment {
+			definitions = append(definitions, t.CommentOnColumn(source, sep))
+		}
+		if target.Position != source.Position {
+			definitions = append(definitions, t.ColumnPositionDefinition(source, sep))
+		}
+		return definitions
+	}
+	if target.Collate != source.Collate || !ColumnTypeChangeSupported(target, source) {
+		return t.RebuildColumnDefinition(target, source, sep)
+	}
+	if target.TypeDefinition() != source.TypeDefinition() {
+		definitions = append(definitions, fmt.Sprintf("ALTER TABLE %s ALTER COLUMN %s TYPE %s%s\n", QuoteIdentifier(t.Name), QuoteIdentifier(source.Name), source.TypeDefinition(), sep))
+	}
+	if target.Default != source.Default {
+		definitions = append(definitions, t.ColumnDefaultDefinition(source, sep))
+	}
+	if target.NotNull != source.NotNull {
+		definitions = append(definitions, t.ColumnNullabilityDefinition(source, sep)...)
+	}
+	if target.Comment != source.Comment {
+		definitions = append(definitions, t.CommentOnColumn(source, sep))
+	}
+	if target.Position != source.Position {
+		definitions = append(definitions, t.ColumnPositionDefinition(source, sep))
+	}
+
+	return definitions
+}
+
+// RebuildColumnDefinition replaces the column by a new one for changes Firebird refuses to do in place
+// (narrowing, BLOB, character set or collation changes). The data is copied through a temporary column
+// with CAST, so the column must not be used by indexes, constraints, views, procedures or triggers.
+func (t *Table) RebuildColumnDefinition(target *TableColumn, source *TableColumn, sep string) []string {
+	definition := source.TypeDefinition()
+	if source.Collate != "" {
+		definition += " COLLATE " + source.Collate
+	}
+	definitions := []string{
+		fmt.Sprintf("ALTER TABLE %s ADD %s %s%s\n", QuoteIdentifier(t.Name), REBUILD_COLUMN_NAME, definition, sep),
+		fmt.Sprintf("UPDATE %s SET %s = CAST(%s AS %s)%s\n", QuoteIdentifier(t.Name), REBUILD_COLUMN_NAME, QuoteIdentifier(target.Name), source.TypeDefinition(), sep),
+		fmt.Sprintf("ALTER TABLE %s DROP %s%s\n", QuoteIdentifier(t.Name), QuoteIdentifier(target.Name), sep),
+		fmt.Sprintf("ALTER TABLE %s ALTER %s TO %s%s\n", QuoteIdentifier(t.Name), REBUILD_COLUMN_NAME, QuoteIdentifier(source.Name), sep),
+		t.ColumnPositionDefinition(source, sep),
+	}
+	if source.Default != "" {
+		definitions = append(definitions, t.ColumnDefaultDefinition(source, sep))
+	}
+	if source.NotNull {
+		definitions = append(definitions, t.ColumnNullabilityDefinition(source, sep)...)
+	}
+	if source.Comment != "" {
+		definitions = append(definitions, t.CommentOnColumn(source, sep))
+	}
+
+	return definitions
+}
+
+func (t *Table) ColumnDefaultDefinition(column *TableColumn, sep string) string {
+	if column.Default == "" {
+		return fmt.Sprintf("ALTER TABLE %s ALTER COLUMN %s DROP DEFAULT%s\n", QuoteIdentifier(t.Name), QuoteIdentifier(column.Name), sep)
+	}
+
+	return fmt.Sprintf("ALTER TABLE %s ALTER COLUMN %s SET %s%s\n", QuoteIdentifier(t.Name), QuoteIdentifier(column.Name), column.Default, sep)
+}
+
+// ColumnNullabilityDefinition changes NOT NULL flag in the metadata, as Firebird 2.5 has no DDL for it.
+// Before the column becomes NOT NULL its nulls are set to the default value, and the change fails
+// with a conversion error naming the column if nulls are still there.
+func (t *Table) ColumnNullabilityDefinition(column *TableColumn, sep string) []string {
+	var definitions []string
+	flag := "NULL"
+	if column.NotNull {
+		flag = "1"
+		if value := DefaultValue(column.Default); value != "" {
+			definitions = append(definitions, fmt.Sprintf("UPDATE %s SET %s = %s WHERE %s IS NULL%s\n", QuoteIdentifier(t.Name), QuoteIdentifier(column.Name), value, QuoteIdentifier(column.Name), sep))
+		}
+		definitions = append(definitions, fmt.Sprintf(`EXECUTE BLOCK AS
+DECLARE VARIABLE HAS_NULLS INTEGER;
+BEGIN
+  IF (EXISTS(SELECT 1 FROM %s WHERE %s IS NULL)) THEN
+    HAS_NULLS = CAST(%s AS INTEGER);
+END%s
+`, QuoteIdentifier(t.Name), QuoteIdentifier(column.Name), StringLiteral(fmt.Sprintf("Column %s.%s has NULL values", t.Name, column.Name)), sep))
+	}
+
+	return append(definitions, fmt.Sprintf("UPDATE RDB$RELATION_FIELDS SET RDB$NULL_FLAG = %s WHERE RDB$FIELD_NAME = %s AND RDB$RELATION_NAME = %s%s\n", flag, StringLiteral(column.Name), StringLiteral(t.Name), sep))
+}
+
+func (t *Table) ColumnPositionDefinition(column *TableColumn, sep string) string {
+	return fmt.Sprintf("ALTER TABLE %s ALTER %s POSITION %d%s\n", QuoteIdentifier(t.Name), QuoteIdentifier(column.Name), column.Position, sep)
+}
+
+// DefaultValue strips DEFAULT keyword from the default source.
+func DefaultValue(defaultSource string) string {
+	value := strings.TrimSpace(defaultSource)
+	if len(value) >= 7 && strings.EqualFold(value[:7], "DEFAULT") {
+		value = strings.TrimSpace(value[7:])
+	}
+
+	return value
+}
+
+func ColumnTypeChangeSupported(from *TableColumn, to *TableColumn) bool {
+	if from.Charset != to.Charset {
+		return false
+	}
+
+	return TypeChangeSupported(from.Type, to.Type)
+}
+
+// TypeChangeSupported tells if ALTER COLUMN TYPE could turn one data type into another one,
+// Firebird allows only conversions which can't lose data.
+func TypeChangeSupported(from string, to string) bool {
+	from, to = strings.ToUpper(strings.TrimSpace(from)), strings.ToUpper(strings.TrimSpace(to))
+	if from == to {
+		return true
+	}
+	fromMatch, toMatch := columnTypeRegexp.FindStringSubmatch(from), columnTypeRegexp.FindStringSubmatch(to)
+	if fromMatch == nil || toMatch == nil {
+		return false
+	}
+	// the catalog reads DOUBLE while files could be written with the DDL name
+	fromType := strings.TrimSuffix(fromMatch[1], " PRECISION")
+	toType := strings.TrimSuffix(toMatch[1], " PRECISION")
+	fromPrecision, _ := strconv.Atoi(fromMatch[2])
+	fromScale, _ := strconv.Atoi(fromMatch[3])
+	toPrecision, _ := strconv.Atoi(toMatch[2])
+	toScale, _ := strconv.Atoi(toMatch[3])
+
+	switch toType {
+	case "CHAR", "VARCHAR":
+		switch fromType {
+		case "CHAR", "VARCHAR":
+			return toPrecision >= fromPrecision
+		case "NUMERIC", "DECIMAL":
+			return toPrecision >= fromPrecision+2
+		case "FLOAT", "DOUBLE":
+			return toPrecision >= 23
+		}
+		if digits, ok := integerDigits[fromType]; ok {
+			return toPrecision > digits
+		}
+		if length, ok := temporalLengths[fromType]; ok {
+			return toPrecision >= length
+		}
+	case "SMALLINT", "INTEGER", "BIGINT":
+		if digits, ok := integerDigits[fromType]; ok {
+			return integerDigits[toType] >= digits
+		}
+	case "NUMERIC", "DECIMAL":
+		switch fromType {
+		case "NUMERIC", "DECIMAL":
+			return toPrecision-toScale >= fromPrecision-fromScale && toScale >= fromScale
+		}
+		if digits, ok := integerDigits[fromType]; ok {
+			return toPrecision-toScale >= digits
+		}
+	case "DOUBLE":
+		switch fromType {
+		case "FLOAT", "NUMERIC", "DECIMAL", "SMALLINT", "INTEGER", "BIGINT":
+			return true
+		}
+	case "FLOAT":
+		return fromType == "SMALLINT"
+	case "TIMESTAMP":
+		return fromType == "DATE"
+	}
+
+	return false
+}
@@ -0,0 +1,76 @@
+package fb
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+
+	"github.com/stpatrickw/sqlrog/internal/sqlrog"
+)
+
+const TABLE_DATA_PRIORITY = 6
+
+var dataDialect = &sqlrog.DataDialect{Quoter: identifierQuoter, StringLiteral: StringLiteral}
+
+func (fb *FirebirdEngine) DataDiff(source *FbSchema, target *FbSchema) *sqlrog.DiffObject {
+	return sqlrog.DataDiff(source.TableData(), target.TableData(), TABLE_DATA_PRIORITY)
+}
+
+func (fbs *FbSchema) TableData() *sqlrog.TableData {
+	return sqlrog.NewTableData(fbs.DataTables(), dataDialect)
+}
+
+func (fbs *FbSchema) TrackData(config *sqlrog.Config) {
+	sqlrog.TrackData(fbs.DataTables(), config)
+}
+
+func (fbs *FbSchema) FetchDataFromDB(ctx context.Context, conn *sql.DB, config *sqlrog.Config) error {
+	return sqlrog.FetchData(ctx, conn, fbs.DataTables(), config, dataDialect)
+}
+
+func (fbs *FbSchema) DataTables() []sqlrog.DataTable {
+	var tables []sqlrog.DataTable
+	for _, element := range fbs.CoreElements[CORE_ELEMENT_TABLE_NAME] {
+		tables = append(tables, element.(*Table))
+	}
+
+	return tables
+}
+
+func (t *Table) GetData() sqlrog.DataRows {
+	return t.Data
+}
+
+func (t *Table) SetData(data sqlrog.DataRows) {
+	t.Data = data
+}
+
+// ReferencedTables returns tables referenced by foreign keys, their rows are inserted first.
+func (t *Table) ReferencedTables() []string {
+	var tables []string
+	for _, foreignKey := range t.Indexes[FOREIGN_KEY] {
+		tables = append(tables, foreignKey.SourceTable)
+	}
+
+	return tables
+}
+
+// DataColumns leaves out computed columns, they are derived from the rest ones.
+func (t *Table) DataColumns() []string {
+	var columns []string
+	for _, column := range OrderedColumnFields(t.Fields) {
+		if column.ComputedBy == "" {
+			columns = append(columns, column.Name)
+		}
+	}
+
+	return columns
+}
+
+func (t *Table) PrimaryKeyColumns() []string {
+	for _, primaryKey := range t.Indexes[PRIMARY_KEY] {
+		return strings.Split(OrderedIndexFields(primaryKey.Fields), ",")
+	}
+
+	return nil
+}
@@ -2,12 +2,21 @@ package fb
 
 import (
 	"bytes"
+	"context"
 	"database/sql"
 	"fmt"
 	"github.com/stpatrickw/sqlrog/internal/sqlrog"
 	"text/template"
 )
 
+const (
+	CORE_ELEMENT_TRIGGER_NAME        = "trigger"
+	CORE_ELEMENT_TRIGGER_PLURAL_NAME = "triggers"
+	TRIGGER_PRIORITY                 = -4
+)
+
+// Trigger is either a table trigger kept by its table or a database trigger
+// (ON CONNECT, ON TRANSACTION COMMIT, etc.) which has no table and is stored under triggers.
 type Trigger struct {
 	sqlrog.BaseElementSchema `yaml:"base,omitempty"`
 	Name                     string
@@ -18,12 +27,34 @@ type Trigger struct {
 	Active                   bool
 }
 
+func (t *Trigger) GetName() string {
+	return t.Name
+}
+
 func (t *Trigger) GetTypeName() string {
-	return "trigger"
+	return CORE_ELEMENT_TRIGGER_NAME
+}
+
+func (t *Trigger) GetPluralTypeName() string {
+	return CORE_ELEMENT_TRIGGER_PLURAL_NAME
+}
+
+// HookParentKey lets hooks of the table surround changes of its triggers, database triggers have hooks of their own.
+func (t *Trigger) HookParentKey() string {
+	if t.TableName == "" {
+		return ""
+	}
+
+	return CORE_ELEMENT_TABLE_PLURAL_NAME + "/" + t.TableName
+}
+
+// GetPriority puts triggers after procedures and views they could use.
+func (t *Trigger) GetPriority() int {
+	return TRIGGER_PRIORITY
 }
 
 func (t *Trigger) AlterDefinition(other interface{}, sep string) []string {
-	return []string{fmt.Sprintf("ALTER %s%s", t.CastType(other).Definition(), sep)}
+	return []string{fmt.Sprintf("CREATE OR ALTER %s%s", t.Definition(), sep)}
 }
 
 func (t *Trigger) CreateDefinition(sep string) []string {
@@ -31,12 +62,12 @@ func (t *Trigger) CreateDefinition(sep string) []string {
 }
 
 func (t *Trigger) DropDefinition(sep string) []string {
-	return []string{fmt.Sprintf("DROP TRIGGER %s%s", t.Name, sep)}
+	return []string{fmt.Sprintf("DROP TRIGGER %s%s", QuoteIdentifier(t.Name), sep)}
 }
 
 func (t *Trigger) Definition() string {
-	procTmpl, err := template.New("procedure").Parse(
-		`TRIGGER {{ .Name }} FOR {{ .TableName }}
+	procTmpl, err := template.New("procedure").Funcs(templateFuncs).Parse(
+		`TRIGGER {{ quote .Name }}{{ if .TableName }} FOR {{ quote .TableName }}{{end}}
 {{ if .Active }}ACTIVE{{ else }}INACTIVE{{end}} {{ .TypeName }} POSITION {{ .Position }}
 {{ .Source }}`)
 
@@ -65,10 +96,11 @@ func (t *Trigger) Diff(t2 interface{}) *sqlrog.DiffObject {
 
 	if !t.Equals(other) {
 		return &sqlrog.DiffObject{
-			State: sqlrog.DIFF_TYPE_UPDATE,
-			Type:  t.GetTypeName(),
-			From:  t,
-			To:    other,
+			State:    sqlrog.DIFF_TYPE_UPDATE,
+			Type:     t.GetTypeName(),
+			From:     t,
+			To:       other,
+			Priority: t.GetPriority(),
 		}
 	}
 
@@ -79,10 +111,12 @@ func (t *Trigger) CastType(other interface{}) *Trigger {
 	return other.(*Trigger)
 }
 
-func (t *Trigger) FetchTriggersFromDB(conn *sql.DB) (map[string]map[string]*Trigger, error) {
+func (t *Trigger) FetchTriggersFromDB(ctx context.Context, conn *sql.DB, filter *sqlrog.ElementFilter) (map[string]map[string]*Trigger, error) {
 	triggers := make(map[string]map[string]*Trigger)
 
-	rows, err := conn.Query(`select
+	condition, args := FilterCondition(filter, "RDB$RELATION_NAME")
+
+	rows, err := conn.QueryContext(ctx, `select
         trim(RDB$RELATION_NAME),
 		trim(RDB$TRIGGER_NAME) as triggerName,
 		case RDB$TRIGGER_INACTIVE when 1 then 0 else 1 end,
@@ -107,7 +141,9 @@ func (t *Trigger) FetchTriggersFromDB(conn *sql.DB) (map[string]map[string]*Trig
 			when 8195 then  'on transaction commit'
 			when 8196 then  'on transaction rollback' end), 
 		RDB$TRIGGER_SEQUENCE, RDB$TRIGGER_SOURCE
-		from RDB$TRIGGERS where RDB$TRIGGER_SOURCE is not null AND RDB$SYSTEM_FLAG = 0`)
+		from RDB$TRIGGERS where RDB$TRIGGER_SOURCE is not null AND RDB$SYSTEM_FLAG = 0
+		and RDB$RELATION_NAME is not null
+		and not exists (select 1 from RDB$CHECK_CONSTRAINTS cc where cc.RDB$TRIGGER_NAME = RDB$TRIGGERS.RDB$TRIGGER_NAME)`+condition, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -128,6 +164,41 @@ func (t *Trigger) FetchTriggersFromDB(conn *sql.DB) (map[string]map[string]*Trig
 	return triggers, nil
 }
 
+func (t *Trigger) FetchElementsFromDB(ctx context.Context, conn *sql.DB, filter *sqlrog.ElementFilter) ([]sqlrog.ElementSchema, error) {
+	var triggers []sqlrog.ElementSchema
+
+	condition, args := FilterCondition(filter, "RDB$TRIGGER_NAME")
+
+	rows, err := conn.QueryContext(ctx, `select
+		trim(RDB$TRIGGER_NAME),
+		case RDB$TRIGGER_INACTIVE when 1 then 0 else 1 end,
+		trim(case RDB$TRIGGER_TYPE
+			when 8192 then  'on connect'
+			when 8193 then  'on disconnect'
+			when 8194 then  'on transaction start'
+			when 8195 then  'on transaction commit'
+			when 8196 then  'on transaction rollback' end),
+		RDB$TRIGGER_SEQUENCE, RDB$TRIGGER_SOURCE
+		from RDB$TRIGGERS where RDB$TRIGGER_SOURCE is not null AND RDB$SYSTEM_FLAG = 0
+		and RDB$RELATION_NAME is null`+condition+`
+		order by 1`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		trigger := &Trigger{}
+		err := rows.Scan(&trigger.Name, &trigger.Active, &trigger.TypeName, &trigger.Position, &trigger.Source)
+		if err != nil {
+			return nil, err
+		}
+		triggers = append(triggers, trigger)
+	}
+
+	return triggers, rows.Err()
+}
+
 func (t *Trigger) DiffsOnCreate(schema sqlrog.ElementSchema) []*sqlrog.DiffObject {
 	return t.BaseElementSchema.DiffsOnCreate(schema)
 }
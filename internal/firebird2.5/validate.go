@@ -0,0 +1,53 @@
+package fb
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/stpatrickw/sqlrog/internal/sqlrog"
+)
+
+// ValidateSchema reports broken files together with the table and trigger errors of the files which are read.
+func (fb *FirebirdEngine) ValidateSchema(ctx context.Context, config *sqlrog.Config, reader sqlrog.ObjectReader) ([]*sqlrog.ValidationError, error) {
+	schema := &FbSchema{
+		BaseElementSchema: sqlrog.BaseElementSchema{
+			CoreElements: make(map[string]map[string]sqlrog.ElementSchema),
+		},
+	}
+	errs := fb.ValidateFiles(config.GetAppName(), schema, reader)
+	tables := schema.CoreElements[CORE_ELEMENT_TABLE_NAME]
+	domains := schema.CoreElements[CORE_ELEMENT_DOMAIN_NAME]
+	for _, element := range tables {
+		table := element.(*Table)
+		path := fb.ElementFilePath(config.GetAppName(), table)
+		for _, message := range table.Validate(tables, domains) {
+			errs = append(errs, &sqlrog.ValidationError{Path: path, Message: message})
+		}
+	}
+	for _, element := range schema.CoreElements[CORE_ELEMENT_TRIGGER_NAME] {
+		trigger := element.(*Trigger)
+		if trigger.TableName != "" {
+			errs = append(errs, &sqlrog.ValidationError{
+				Path:    fb.ElementFilePath(config.GetAppName(), trigger),
+				Message: fmt.Sprintf("database trigger '%s' belongs to table '%s'", trigger.Name, trigger.TableName),
+			})
+		}
+	}
+	sqlrog.SortValidationErrors(errs)
+
+	return errs, nil
+}
+
+func (t *Table) Validate(tables map[string]sqlrog.ElementSchema, domains map[string]sqlrog.ElementSchema) []string {
+	messages := sqlrog.ValidateTable(t, tables, FOREIGN_KEY, CHECK)
+	for name, column := range t.Fields {
+		if column.Domain == "" {
+			continue
+		}
+		if _, ok := domains[column.Domain]; !ok {
+			messages = append(messages, fmt.Sprintf("column '%s' uses unknown domain '%s'", name, column.Domain))
+		}
+	}
+
+	return messages
+}
@@ -2,6 +2,7 @@ package fb
 
 import (
 	"bytes"
+	"context"
 	"database/sql"
 	"fmt"
 	"github.com/stpatrickw/sqlrog/internal/sqlrog"
@@ -11,6 +12,7 @@ import (
 const (
 	CORE_ELEMENT_VIEW_NAME        = "view"
 	CORE_ELEMENT_VIEW_PLURAL_NAME = "views"
+	VIEW_PRIORITY                 = -2
 )
 
 type View struct {
@@ -31,8 +33,13 @@ func (v *View) GetPluralTypeName() string {
 	return CORE_ELEMENT_VIEW_PLURAL_NAME
 }
 
+// GetPriority puts views after procedure stubs, views could select from procedures.
+func (v *View) GetPriority() int {
+	return VIEW_PRIORITY
+}
+
 func (v *View) AlterDefinition(other interface{}, sep string) []string {
-	return []string{fmt.Sprintf("ALTER %s", v.CastType(other).Definition(sep))}
+	return []string{fmt.Sprintf("ALTER %s", v.Definition(sep))}
 }
 
 func (v *View) CreateDefinition(sep string) []string {
@@ -40,11 +47,11 @@ func (v *View) CreateDefinition(sep string) []string {
 }
 
 func (v *View) DropDefinition(sep string) []string {
-	return []string{fmt.Sprintf("DROP VIEW %s%s", v.Name, sep)}
+	return []string{fmt.Sprintf("DROP VIEW %s%s", QuoteIdentifier(v.Name), sep)}
 }
 
 func (v *View) Definition(sep string) string {
-	procTmpl, err := template.New("view").Parse(`VIEW {{ .Name}} 
+	procTmpl, err := template.New("view").Funcs(templateFuncs).Parse(`VIEW {{ quote .Name}} 
 as {{ .Source }}`)
 
 	if err != nil {
@@ -75,10 +82,11 @@ func (v *View) Diff(e2 interface{}) *sqlrog.DiffObject {
 
 	if !v.Equals(other) {
 		return &sqlrog.DiffObject{
-			State: sqlrog.DIFF_TYPE_UPDATE,
-			Type:  v.GetTypeName(),
-			From:  v,
-			To:    other,
+			State:    sqlrog.DIFF_TYPE_UPDATE,
+			Type:     v.GetTypeName(),
+			From:     v,
+			To:       other,
+			Priority: v.GetPriority(),
 		}
 	}
 
@@ -89,15 +97,17 @@ func (v *View) CastType(other interface{}) *View {
 	return other.(*View)
 }
 
-func (v *View) FetchElementsFromDB(conn *sql.DB) ([]sqlrog.ElementSchema, error) {
+func (v *View) FetchElementsFromDB(ctx context.Context, conn *sql.DB, filter *sqlrog.ElementFilter) ([]sqlrog.ElementSchema, error) {
 	var views []sqlrog.ElementSchema
 
-	rows, err := conn.Query(`
+	condition, args := FilterCondition(filter, "rdb$relation_name")
+
+	rows, err := conn.QueryContext(ctx, `
 		select trim(rdb$relation_name), trim(rdb$view_source)
 			from rdb$relations
 			where rdb$view_blr is not null
-			and (rdb$system_flag is null or rdb$system_flag = 0)
-		order by 1`)
+			and (rdb$system_flag is null or rdb$system_flag = 0)`+condition+`
+		order by 1`, args...)
 	if err != nil {
 		return views, err
 	}
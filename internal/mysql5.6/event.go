@@ -0,0 +1,173 @@
+package mysql
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"fmt"
+	"regexp"
+	"text/template"
+
+	"github.com/stpatrickw/sqlrog/internal/sqlrog"
+)
+
+const (
+	CORE_ELEMENT_EVENT_NAME        = "event"
+	CORE_ELEMENT_EVENT_PLURAL_NAME = "events"
+)
+
+var (
+	eventStatuses = map[string]string{
+		"ENABLED":            "ENABLE",
+		"DISABLED":           "DISABLE",
+		"SLAVESIDE_DISABLED": "DISABLE ON SLAVE",
+	}
+	intervalNumberRegexp = regexp.MustCompile(`^[0-9]+$`)
+)
+
+type Event struct {
+	sqlrog.BaseElementSchema `yaml:"base,omitempty"`
+	Name                     string `yaml:"name"`
+	Definer                  string `yaml:"definer"`
+	ExecuteAt                string `yaml:"execute_at,omitempty"`
+	IntervalValue            string `yaml:"interval_value,omitempty"`
+	IntervalField            string `yaml:"interval_field,omitempty"`
+	Starts                   string `yaml:"starts,omitempty"`
+	Ends                     string `yaml:"ends,omitempty"`
+	Status                   string `yaml:"status"`
+	OnCompletion             string `yaml:"on_completion"`
+	Comment                  string `yaml:"comment,omitempty"`
+	Source                   string `yaml:"source"`
+}
+
+func (e *Event) GetName() string {
+	return e.Name
+}
+
+func (e *Event) GetTypeName() string {
+	return CORE_ELEMENT_EVENT_NAME
+}
+
+func (e *Event) GetPluralTypeName() string {
+	return CORE_ELEMENT_EVENT_PLURAL_NAME
+}
+
+func (e *Event) AlterDefinition(other interface{}, sep string) []string {
+	return []string{fmt.Sprintf("ALTER %s", e.Definition(sep))}
+}
+
+func (e *Event) CreateDefinition(sep string) []string {
+	return []string{fmt.Sprintf("CREATE %s", e.Definition(sep))}
+}
+
+func (e *Event) DropDefinition(sep string) []string {
+	return []string{fmt.Sprintf("DROP EVENT IF EXISTS %s%s", QuoteIdentifier(e.Name), sep)}
+}
+
+func (e *Event) Definition(sep string) string {
+	eventTmpl, err := template.New("event").Funcs(templateFuncs).Parse(`{{if .Definer}}DEFINER={{ .DefinerDefinition}} {{end}}EVENT {{ quote .Name}}
+ON SCHEDULE {{ .ScheduleDefinition}}
+ON COMPLETION {{ .OnCompletion}}
+{{ .StatusDefinition}}{{if .Comment}}
+COMMENT {{ literal .Comment}}{{end}}
+DO {{ .Source }}`)
+
+	if err != nil {
+		return ""
+	}
+	var tpl bytes.Buffer
+
+	err = eventTmpl.Execute(&tpl, e)
+	if err != nil {
+		return ""
+	}
+
+	return tpl.String() + sep
+}
+
+func (e *Event) DefinerDefinition() string {
+	return DefinerDefinition(e.Definer)
+}
+
+func (e *Event) ScheduleDefinition() string {
+	if e.ExecuteAt != "" {
+		return "AT " + StringLiteral(e.ExecuteAt)
+	}
+	interval := e.IntervalValue
+	if !intervalNumberRegexp.MatchString(interval) {
+		interval = StringLiteral(interval)
+	}
+	schedule := fmt.Sprintf("EVERY %s %s", interval, e.IntervalField)
+	if e.Starts != "" {
+		schedule += " STARTS " + StringLiteral(e.Starts)
+	}
+	if e.Ends != "" {
+		schedule += " ENDS " + StringLiteral(e.Ends)
+	}
+
+	return schedule
+}
+
+func (e *Event) StatusDefinition() string {
+	if status, ok := eventStatuses[e.Status]; ok {
+		return status
+	}
+
+	return "ENABLE"
+}
+
+func (e *Event) Equals(e2 interface{}) bool {
+	other := e.CastType(e2)
+
+	return e.Name == other.Name && e.Definer == other.Definer && e.ExecuteAt == other.ExecuteAt &&
+		e.IntervalValue == other.IntervalValue && e.IntervalField == other.IntervalField &&
+		e.Starts == other.Starts && e.Ends == other.Ends && e.Status == other.Status &&
+		e.OnCompletion == other.OnCompletion && e.Comment == other.Comment && e.Source == other.Source
+}
+
+func (e *Event) Diff(e2 interface{}) *sqlrog.DiffObject {
+	other := e.CastType(e2)
+
+	if !e.Equals(other) {
+		return &sqlrog.DiffObject{
+			State: sqlrog.DIFF_TYPE_UPDATE,
+			Type:  e.GetTypeName(),
+			From:  e,
+			To:    other,
+		}
+	}
+
+	return nil
+}
+
+func (e *Event) CastType(other interface{}) *Event {
+	return other.(*Event)
+}
+
+func (e *Event) FetchElementsFromDB(ctx context.Context, conn *sql.DB, filter *sqlrog.ElementFilter) ([]sqlrog.ElementSchema, error) {
+	var events []sqlrog.ElementSchema
+
+	condition, args := FilterCondition(filter, "EVENT_NAME")
+
+	rows, err := conn.QueryContext(ctx, `
+		SELECT EVENT_NAME, DEFINER, coalesce(EXECUTE_AT, ''), coalesce(INTERVAL_VALUE, ''), coalesce(INTERVAL_FIELD, ''),
+			coalesce(STARTS, ''), coalesce(ENDS, ''), STATUS, ON_COMPLETION, EVENT_COMMENT, EVENT_DEFINITION
+		FROM INFORMATION_SCHEMA.EVENTS
+		WHERE EVENT_SCHEMA = schema()`+condition+`
+		ORDER BY 1`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		event := &Event{}
+		err := rows.Scan(&event.Name, &event.Definer, &event.ExecuteAt, &event.IntervalValue, &event.IntervalField,
+			&event.Starts, &event.Ends, &event.Status, &event.OnCompletion, &event.Comment, &event.Source)
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, event)
+	}
+
+	return events, rows.Err()
+}
@@ -2,6 +2,7 @@ package mysql
 
 import (
 	"bytes"
+	"context"
 	"database/sql"
 	"fmt"
 	"github.com/stpatrickw/sqlrog/internal/sqlrog"
@@ -21,6 +22,7 @@ type Function struct {
 	OutputParameterType      string                        `yaml:"output_parameter_type"`
 	OutputParameterCharset   string                        `yaml:"output_parameter_charset"`
 	Deterministic            bool
+	RoutineCharacteristics   `yaml:",inline"`
 }
 
 type FunctionParameter struct {
@@ -44,21 +46,21 @@ func (f *Function) GetPluralTypeName() string {
 }
 
 func (f *Function) AlterDefinition(other interface{}, sep string) []string {
-	return append(f.CastType(other).DropDefinition(sep), f.CastType(other).CreateDefinition(sep)...)
+	return append(f.DropDefinition(sep), f.CreateDefinition(sep)...)
 }
 
 func (f *Function) CreateDefinition(sep string) []string {
-	return []string{fmt.Sprintf("CREATE %s", f.Definition(sep))}
+	return f.WithSqlMode([]string{fmt.Sprintf("CREATE %s", f.Definition(sep))}, sep)
 }
 
 func (f *Function) DropDefinition(sep string) []string {
-	return []string{fmt.Sprintf("DROP FUNCTION IF EXISTS %s%s", f.Name, sep)}
+	return []string{fmt.Sprintf("DROP FUNCTION IF EXISTS %s%s", QuoteIdentifier(f.Name), sep)}
 }
 
 func (f *Function) Definition(sep string) string {
-	procTmpl, err := template.New("function").Parse(`FUNCTION ` + "{{ .Name}}" + `({{if .InputParameters}}
+	procTmpl, err := template.New("function").Funcs(templateFuncs).Parse(`{{if .Definer}}DEFINER={{ .DefinerDefinition}} {{end}}FUNCTION ` + "{{ quote .Name}}" + `({{if .InputParameters}}
 	{{$first := true}}{{range $index, $element := .InputParameters}}{{if $first}}{{$first = false}}{{else}},
-	{{end}}{{.Name}} {{.TypeName}}{{if ne .Charset "" }} CHARSET {{.Charset}}{{end}}{{end}}{{end}}) RETURNS {{ .OutputParameterType}}{{if ne .OutputParameterCharset "" }} CHARSET {{ .OutputParameterCharset}}{{end}}{{if .Deterministic}} DETERMINISTIC{{end}}
+	{{end}}{{quote .Name}} {{.TypeName}}{{if ne .Charset "" }} CHARSET {{.Charset}}{{end}}{{end}}{{end}}) RETURNS {{ .OutputParameterType}}{{if ne .OutputParameterCharset "" }} CHARSET {{ .OutputParameterCharset}}{{end}}{{if .Deterministic}} DETERMINISTIC{{end}}{{ .CharacteristicsDefinition}}
 {{ .Source }}`)
 
 	if err != nil {
@@ -78,7 +80,8 @@ func (f *Function) Equals(e2 interface{}) bool {
 	other := f.CastType(e2)
 
 	if f.Name != other.Name || f.Source != other.Source || f.Deterministic != other.Deterministic ||
-		f.OutputParameterType != other.OutputParameterType || f.OutputParameterCharset != other.OutputParameterCharset {
+		f.OutputParameterType != other.OutputParameterType || f.OutputParameterCharset != other.OutputParameterCharset ||
+		f.RoutineCharacteristics != other.RoutineCharacteristics {
 		return false
 	}
 
@@ -133,13 +136,16 @@ func (f *Function) CastType(other interface{}) *Function {
 	return other.(*Function)
 }
 
-func (f *Function) FetchElementsFromDB(conn *sql.DB) ([]sqlrog.ElementSchema, error) {
+func (f *Function) FetchElementsFromDB(ctx context.Context, conn *sql.DB, filter *sqlrog.ElementFilter) ([]sqlrog.ElementSchema, error) {
 	var functions []sqlrog.ElementSchema
 
-	rows, err := conn.Query(`SELECT r.SPECIFIC_NAME, r.ROUTINE_DEFINITION, p.DTD_IDENTIFIER, coalesce(p.CHARACTER_SET_NAME,''), r.IS_DETERMINISTIC
+	functionCondition, functionArgs := FilterCondition(filter, "r.SPECIFIC_NAME")
+
+	rows, err := conn.QueryContext(ctx, `SELECT r.SPECIFIC_NAME, r.ROUTINE_DEFINITION, p.DTD_IDENTIFIER, coalesce(p.CHARACTER_SET_NAME,''), r.IS_DETERMINISTIC,
+			r.DEFINER, r.SECURITY_TYPE, r.SQL_DATA_ACCESS, r.SQL_MODE, r.ROUTINE_COMMENT
 		FROM information_schema.routines r
 		JOIN information_schema.parameters p on p.specific_name = r.specific_name and p.parameter_mode is null
-		WHERE r.ROUTINE_SCHEMA = schema() AND r.routine_type = 'FUNCTION' order by 1`)
+		WHERE r.ROUTINE_SCHEMA = schema() AND r.routine_type = 'FUNCTION'`+functionCondition+` order by 1`, functionArgs...)
 	if err != nil {
 		return nil, err
 	}
@@ -147,8 +153,9 @@ func (f *Function) FetchElementsFromDB(conn *sql.DB) ([]sqlrog.ElementSchema, er
 	parametersQuery := `SELECT SPECIFIC_NAME, PARAMETER_NAME, PARAMETER_MODE, DTD_IDENTIFIER, ORDINAL_POSITION, coalesce(CHARACTER_SET_NAME,'')
 		FROM information_schema.parameters
 		WHERE SPECIFIC_SCHEMA = schema() AND ROUTINE_TYPE = 'FUNCTION' AND PARAMETER_MODE = 'IN'`
+	parametersCondition, parametersArgs := FilterCondition(filter, "SPECIFIC_NAME")
 
-	parameterRows, err := conn.Query(parametersQuery)
+	parameterRows, err := conn.QueryContext(ctx, parametersQuery+parametersCondition, parametersArgs...)
 	if err != nil {
 		return nil, err
 	}
@@ -172,7 +179,8 @@ func (f *Function) FetchElementsFromDB(conn *sql.DB) ([]sqlrog.ElementSchema, er
 	for rows.Next() {
 		var deterministic string
 		function := &Function{InputParameters: make(map[string]*FunctionParameter)}
-		err := rows.Scan(&function.Name, &function.Source, &function.OutputParameterType, &function.OutputParameterCharset, &deterministic)
+		err := rows.Scan(&function.Name, &function.Source, &function.OutputParameterType, &function.OutputParameterCharset, &deterministic,
+			&function.Definer, &function.SqlSecurity, &function.DataAccess, &function.SqlMode, &function.Comment)
 		if err != nil {
 			return nil, err
 		}
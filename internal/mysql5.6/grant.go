@@ -0,0 +1,208 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/stpatrickw/sqlrog/internal/sqlrog"
+)
+
+const (
+	CORE_ELEMENT_GRANT_NAME        = "grant"
+	CORE_ELEMENT_GRANT_PLURAL_NAME = "grants"
+	GRANT_PRIORITY                 = -1
+	GRANT_OBJECT_TABLE             = "TABLE"
+)
+
+type Grant struct {
+	sqlrog.BaseElementSchema `yaml:"base,omitempty"`
+	Grantee                  string              `yaml:"grantee"`
+	Privileges               []*sqlrog.Privilege `yaml:"privileges"`
+}
+
+func (g *Grant) GetName() string {
+	return g.Grantee
+}
+
+func (g *Grant) GetTypeName() string {
+	return CORE_ELEMENT_GRANT_NAME
+}
+
+func (g *Grant) GetPluralTypeName() string {
+	return CORE_ELEMENT_GRANT_PLURAL_NAME
+}
+
+// GetPriority puts grants after all objects are created.
+func (g *Grant) GetPriority() int {
+	return GRANT_PRIORITY
+}
+
+func (g *Grant) AlterDefinition(other interface{}, sep string) []string {
+	var definitions []string
+	added, removed := sqlrog.PrivilegesDiff(g.Privileges, g.CastType(other).Privileges)
+	for _, privilege := range removed {
+		definitions = append(definitions, g.RevokeDefinition(privilege, sep))
+	}
+	for _, privilege := range added {
+		definitions = append(definitions, g.GrantDefinition(privilege, sep))
+	}
+
+	return definitions
+}
+
+func (g *Grant) CreateDefinition(sep string) []string {
+	return g.AlterDefinition(&Grant{Grantee: g.Grantee}, sep)
+}
+
+func (g *Grant) DropDefinition(sep string) []string {
+	return (&Grant{Grantee: g.Grantee}).AlterDefinition(g, sep)
+}
+
+func (g *Grant) GrantDefinition(privilege *sqlrog.Privilege, sep string) string {
+	definition := fmt.Sprintf("GRANT %s ON %s TO %s", g.PrivilegeDefinition(privilege), g.ObjectDefinition(privilege), g.GranteeDefinition())
+	if privilege.GrantOption {
+		definition += " WITH GRANT OPTION"
+	}
+
+	return definition + sep
+}
+
+func (g *Grant) RevokeDefinition(privilege *sqlrog.Privilege, sep string) string {
+	privileges := g.PrivilegeDefinition(privilege)
+	if privilege.GrantOption {
+		privileges += ", GRANT OPTION"
+	}
+
+	return fmt.Sprintf("REVOKE %s ON %s FROM %s%s", privileges, g.ObjectDefinition(privilege), g.GranteeDefinition(), sep)
+}
+
+func (g *Grant) PrivilegeDefinition(privilege *sqlrog.Privilege) string {
+	if privilege.Column != "" {
+		return fmt.Sprintf("%s (%s)", privilege.Privilege, QuoteIdentifier(privilege.Column))
+	}
+
+	return privilege.Privilege
+}
+
+func (g *Grant) ObjectDefinition(privilege *sqlrog.Privilege) string {
+	return fmt.Sprintf("%s %s", privilege.ObjectType, QuoteIdentifier(privilege.Object))
+}
+
+// GranteeDefinition turns user@host name into 'user'@'host' account.
+func (g *Grant) GranteeDefinition() string {
+	user, host := g.Grantee, "%"
+	if i := strings.LastIndex(g.Grantee, "@"); i >= 0 {
+		user, host = g.Grantee[:i], g.Grantee[i+1:]
+	}
+
+	return StringLiteral(user) + "@" + StringLiteral(host)
+}
+
+func (g *Grant) Equals(e2 interface{}) bool {
+	other := g.CastType(e2)
+
+	return g.Grantee == other.Grantee && sqlrog.PrivilegesEqual(g.Privileges, other.Privileges)
+}
+
+func (g *Grant) Diff(e2 interface{}) *sqlrog.DiffObject {
+	other := g.CastType(e2)
+
+	if !g.Equals(other) {
+		return &sqlrog.DiffObject{
+			State:    sqlrog.DIFF_TYPE_UPDATE,
+			Type:     g.GetTypeName(),
+			From:     g,
+			To:       other,
+			Priority: GRANT_PRIORITY,
+		}
+	}
+
+	return nil
+}
+
+func (g *Grant) CastType(other interface{}) *Grant {
+	return other.(*Grant)
+}
+
+func (g *Grant) FetchElementsFromDB(ctx context.Context, conn *sql.DB, filter *sqlrog.ElementFilter) ([]sqlrog.ElementSchema, error) {
+	grantsMap := make(map[string]*Grant)
+	grantee := func(name string) *Grant {
+		if _, ok := grantsMap[name]; !ok {
+			grantsMap[name] = &Grant{Grantee: name}
+		}
+		return grantsMap[name]
+	}
+
+	granteeColumn := "REPLACE(GRANTEE, '''', '')"
+	condition, args := FilterCondition(filter, granteeColumn)
+	rows, err := conn.QueryContext(ctx, `
+		SELECT `+granteeColumn+`, TABLE_NAME, '' as COLUMN_NAME, PRIVILEGE_TYPE, IS_GRANTABLE
+		FROM INFORMATION_SCHEMA.TABLE_PRIVILEGES
+		WHERE TABLE_SCHEMA = schema()`+condition+`
+		UNION ALL
+		SELECT `+granteeColumn+`, TABLE_NAME, COLUMN_NAME, PRIVILEGE_TYPE, IS_GRANTABLE
+		FROM INFORMATION_SCHEMA.COLUMN_PRIVILEGES
+		WHERE TABLE_SCHEMA = schema()`+condition+`
+		ORDER BY 1, 2, 3, 4`, append(args, args...)...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var name, grantable string
+		privilege := &sqlrog.Privilege{ObjectType: GRANT_OBJECT_TABLE}
+		if err := rows.Scan(&name, &privilege.Object, &privilege.Column, &privilege.Privilege, &grantable); err != nil {
+			return nil, err
+		}
+		privilege.GrantOption = grantable == "YES"
+		grant := grantee(name)
+		grant.Privileges = append(grant.Privileges, privilege)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	routineGrantee := "CONCAT(User, '@', Host)"
+	condition, args = FilterCondition(filter, routineGrantee)
+	routineRows, err := conn.QueryContext(ctx, `
+		SELECT `+routineGrantee+`, Routine_name, Routine_type, Proc_priv
+		FROM mysql.procs_priv
+		WHERE Db = schema()`+condition+`
+		ORDER BY 1, 2`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer routineRows.Close()
+	for routineRows.Next() {
+		var name, routine, routineType, routinePrivileges string
+		if err := routineRows.Scan(&name, &routine, &routineType, &routinePrivileges); err != nil {
+			return nil, err
+		}
+		grantOption := strings.Contains(routinePrivileges, "Grant")
+		grant := grantee(name)
+		for _, routinePrivilege := range strings.Split(routinePrivileges, ",") {
+			if routinePrivilege == "" || routinePrivilege == "Grant" {
+				continue
+			}
+			grant.Privileges = append(grant.Privileges, &sqlrog.Privilege{
+				Privilege:   strings.ToUpper(routinePrivilege),
+				ObjectType:  strings.ToUpper(routineType),
+				Object:      routine,
+				GrantOption: grantOption,
+			})
+		}
+	}
+	if err := routineRows.Err(); err != nil {
+		return nil, err
+	}
+
+	var grants []sqlrog.ElementSchema
+	for _, grant := range grantsMap {
+		sqlrog.SortPrivileges(grant.Privileges)
+		grants = append(grants, grant)
+	}
+
+	return grants, nil
+}
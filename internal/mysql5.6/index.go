@@ -1,6 +1,7 @@
 package mysql
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
 	"github.com/stpatrickw/sqlrog/internal/sqlrog"
@@ -13,6 +14,8 @@ const (
 	FOREIGN_KEY          = "FOREIGN KEY"
 	UNIQUE               = "UNIQUE"
 	INDEX                = "INDEX"
+	FULLTEXT             = "FULLTEXT"
+	SPATIAL              = "SPATIAL"
 	PRIMARY_KEY_PRIORITY = 9
 	FOREIGN_KEY_PRIORITY = 7
 	UNIQUE_PRIORITY      = 8
@@ -31,17 +34,24 @@ type Index struct {
 	SourceFields             map[string]IndexField
 	OnDelete                 string
 	OnUpdate                 string
+	Comment                  string `yaml:"comment,omitempty"`
 }
 
 type IndexField struct {
 	Name     string
 	Position int
+	Length   int `yaml:"length,omitempty"`
 }
 
 func (i *Index) GetTypeName() string {
 	return "index"
 }
 
+// HookParentKey lets hooks of the table surround changes of its indexes.
+func (i *Index) HookParentKey() string {
+	return CORE_ELEMENT_TABLE_PLURAL_NAME + "/" + i.TableName
+}
+
 func (i *Index) AlterDefinition(other interface{}, sep string) []string {
 	i2 := i.CastType(other)
 	definitions := i.DropDefinition(sep)
@@ -57,7 +67,7 @@ func (i *Index) CreateDefinition(sep string) []string {
 }
 
 func (i *Index) DropDefinition(sep string) []string {
-	return []string{fmt.Sprintf("ALTER TABLE %s DROP %s %s%s", i.TableName, i.Type, i.Name, sep)}
+	return []string{fmt.Sprintf("ALTER TABLE %s DROP %s %s%s", QuoteIdentifier(i.TableName), i.Type, QuoteIdentifier(i.Name), sep)}
 }
 
 func (i *Index) Definition(sep string) string {
@@ -66,12 +76,12 @@ func (i *Index) Definition(sep string) string {
 
 	switch i.Type {
 	case PRIMARY_KEY:
-		definition = fmt.Sprintf("ALTER TABLE %s ADD CONSTRAINT %s (%s)", i.TableName, i.Type, OrderedIndexFields(i.Fields))
+		definition = fmt.Sprintf("ALTER TABLE %s ADD CONSTRAINT %s (%s)%s", QuoteIdentifier(i.TableName), i.Type, IndexFieldsDefinition(i.Fields), i.OptionsDefinition())
 	case UNIQUE:
-		definition = fmt.Sprintf("ALTER TABLE %s ADD CONSTRAINT %s %s (%s)", i.TableName, i.Name, i.Type, OrderedIndexFields(i.Fields))
+		definition = fmt.Sprintf("ALTER TABLE %s ADD CONSTRAINT %s %s (%s)%s", QuoteIdentifier(i.TableName), QuoteIdentifier(i.Name), i.Type, IndexFieldsDefinition(i.Fields), i.OptionsDefinition())
 	case FOREIGN_KEY:
 		definition = fmt.Sprintf("ALTER TABLE %s ADD CONSTRAINT %s %s (%s) REFERENCES %s (%s)",
-			i.TableName, i.Name, i.Type, OrderedIndexFields(i.Fields), i.SourceTable, OrderedIndexFields(i.SourceFields))
+			QuoteIdentifier(i.TableName), QuoteIdentifier(i.Name), i.Type, QuotedIndexFields(i.Fields), QuoteIdentifier(i.SourceTable), QuotedIndexFields(i.SourceFields))
 		if i.OnDelete != "" {
 			definition += " ON DELETE " + i.OnDelete
 		}
@@ -79,18 +89,47 @@ func (i *Index) Definition(sep string) string {
 			definition += " ON UPDATE " + i.OnUpdate
 		}
 	case INDEX:
-		unique := ""
+		kind := ""
 		if i.Unique {
-			unique = " UNIQUE"
+			kind = " UNIQUE"
+		}
+		if i.Algorithm == FULLTEXT || i.Algorithm == SPATIAL {
+			kind = " " + i.Algorithm
 		}
-		order := ""
 
-		definition = fmt.Sprintf("CREATE%s%s INDEX %s ON %s (%s)", unique, order, i.Name, i.TableName, OrderedIndexFields(i.Fields))
+		definition = fmt.Sprintf("CREATE%s INDEX %s ON %s (%s)%s", kind, QuoteIdentifier(i.Name), QuoteIdentifier(i.TableName), IndexFieldsDefinition(i.Fields), i.OptionsDefinition())
 	}
 
 	return definition + sep
 }
 
+// OptionsDefinition returns index type (BTREE/HASH) and comment options.
+func (i *Index) OptionsDefinition() string {
+	var definition string
+	if i.Algorithm != "" && i.Algorithm != FULLTEXT && i.Algorithm != SPATIAL {
+		definition += " USING " + i.Algorithm
+	}
+	if i.Comment != "" {
+		definition += " COMMENT " + StringLiteral(i.Comment)
+	}
+
+	return definition
+}
+
+// IndexFieldsDefinition returns ordered index fields with their prefix lengths.
+func IndexFieldsDefinition(fields map[string]IndexField) string {
+	var definitions []string
+	for _, field := range strings.Split(OrderedIndexFields(fields), ",") {
+		definition := QuoteIdentifier(field)
+		if length := fields[field].Length; length > 0 {
+			definition = fmt.Sprintf("%s(%d)", definition, length)
+		}
+		definitions = append(definitions, definition)
+	}
+
+	return strings.Join(definitions, ",")
+}
+
 func OrderedIndexFields(fields map[string]IndexField) string {
 	var indexFields []IndexField
 	for _, indexField := range fields {
@@ -107,11 +146,16 @@ func OrderedIndexFields(fields map[string]IndexField) string {
 	return strings.Join(stringFields, ",")
 }
 
+func QuotedIndexFields(fields map[string]IndexField) string {
+	return strings.Join(QuoteIdentifiers(strings.Split(OrderedIndexFields(fields), ",")), ",")
+}
+
 func (i *Index) Equals(i2 interface{}) bool {
 	other := i.CastType(i2)
 
 	if i.Name != other.Name || i.TableName != other.TableName || i.SourceTable != other.SourceTable ||
-		i.OnDelete != other.OnDelete || i.OnUpdate != other.OnUpdate {
+		i.OnDelete != other.OnDelete || i.OnUpdate != other.OnUpdate || i.Unique != other.Unique ||
+		i.Algorithm != other.Algorithm || i.Comment != other.Comment {
 		return false
 	}
 
@@ -176,7 +220,7 @@ func IndexFieldsEqual(src map[string]IndexField, dest map[string]IndexField) boo
 }
 
 func IndexFieldEquals(src IndexField, dest IndexField) bool {
-	return src.Name == dest.Name && src.Position == dest.Position
+	return src.Name == dest.Name && src.Position == dest.Position && src.Length == dest.Length
 }
 
 func (i *Index) CastType(other interface{}) *Index {
@@ -207,24 +251,26 @@ func (i *Index) GetPriorityByType(Type string, drop bool) int {
 	}
 }
 
-func (i *Index) FetchIndexesFromDB(conn *sql.DB) (map[string]map[string]map[string]*Index, error) {
-
+func (i *Index) FetchIndexesFromDB(ctx context.Context, conn *sql.DB, filter *sqlrog.ElementFilter) (map[string]map[string]map[string]*Index, error) {
+	indexCondition, indexArgs := FilterCondition(filter, "i.table_name")
+	foreignCondition, foreignArgs := FilterCondition(filter, "c.table_name")
 	indexQuery := `
 		select i.table_name, i.index_name, i.non_unique, 
 			i.seq_in_index as position, i.column_name, i.index_type, 
-            coalesce(c.constraint_type, 'INDEX'), '', '', 0, '', ''
+            coalesce(c.constraint_type, 'INDEX'), '', '', 0, '', '', coalesce(i.sub_part, 0), i.index_comment
         from INFORMATION_SCHEMA.STATISTICS i
 		left join INFORMATION_SCHEMA.TABLE_CONSTRAINTS c on i.index_name = c.constraint_name and i.table_schema = c.constraint_schema
-        WHERE i.table_schema = schema()
+        WHERE i.table_schema = schema()` + indexCondition + `
         union all 
         select c.table_name, c.constraint_name as index_name, 1 as non_unique,
 			k.ordinal_position as position, k.column_name, '' as index_type,
-            c.constraint_type, r.update_rule, r.delete_rule, k.position_in_unique_constraint, k.referenced_table_name, k.referenced_column_name
+            c.constraint_type, r.update_rule, r.delete_rule, k.position_in_unique_constraint, k.referenced_table_name, k.referenced_column_name,
+            0, ''
         from INFORMATION_SCHEMA.TABLE_CONSTRAINTS c
         join INFORMATION_SCHEMA.REFERENTIAL_CONSTRAINTS r on r.constraint_schema = c.constraint_schema and r.constraint_name = c.constraint_name
         join INFORMATION_SCHEMA.KEY_COLUMN_USAGE k on k.constraint_schema = c.constraint_schema and k.constraint_name = c.constraint_name
-        where c.constraint_schema = schema() and c.constraint_type = 'FOREIGN KEY'`
-	indexRows, err := conn.Query(indexQuery)
+        where c.constraint_schema = schema() and c.constraint_type = 'FOREIGN KEY'` + foreignCondition
+	indexRows, err := conn.QueryContext(ctx, indexQuery, append(indexArgs, foreignArgs...)...)
 	if err != nil {
 		return nil, err
 	}
@@ -248,10 +294,18 @@ func (i *Index) FetchIndexesFromDB(conn *sql.DB) (map[string]map[string]map[stri
 			&tableIndex.OnDelete,
 			&sourceField.Position,
 			&tableIndex.SourceTable,
-			&sourceField.Name)
+			&sourceField.Name,
+			&indexField.Length,
+			&tableIndex.Comment)
 		if err != nil {
 			return nil, err
 		}
+		if tableIndex.Type == FOREIGN_KEY {
+			// foreign keys are listed in STATISTICS too by their supporting indexes, which attributes don't belong to the constraint
+			tableIndex.Algorithm = ""
+			tableIndex.Comment = ""
+			indexField.Length = 0
+		}
 		if nonUnique == 0 {
 			tableIndex.Unique = true
 		}
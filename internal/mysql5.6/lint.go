@@ -0,0 +1,122 @@
+package mysql
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/stpatrickw/sqlrog/internal/sqlrog"
+)
+
+func (my *MysqlEngine) GetLintRules() []*sqlrog.LintRule {
+	return append(sqlrog.TableLintRules(), []*sqlrog.LintRule{
+		{
+			Name:        "foreign_key_without_index",
+			Description: "Foreign key columns should be covered by an index",
+			Severity:    sqlrog.LINT_SEVERITY_WARNING,
+			Check: func(schema sqlrog.ElementSchema, config *sqlrog.LintConfig) []*sqlrog.LintIssue {
+				var issues []*sqlrog.LintIssue
+				for _, element := range sqlrog.LintTables(schema) {
+					table := element.(*Table)
+					for _, foreignKey := range table.Indexes[FOREIGN_KEY] {
+						if !table.HasIndexOn(foreignKey.Fields) {
+							issues = append(issues, sqlrog.TableLintIssue(table, fmt.Sprintf("foreign key %s (%s) has no supporting index", foreignKey.Name, OrderedIndexFields(foreignKey.Fields))))
+						}
+					}
+				}
+				return issues
+			},
+		},
+		{
+			Name:        "mixed_collations",
+			Description: "Column collations should match the table collation",
+			Severity:    sqlrog.LINT_SEVERITY_INFO,
+			Check: func(schema sqlrog.ElementSchema, config *sqlrog.LintConfig) []*sqlrog.LintIssue {
+				var issues []*sqlrog.LintIssue
+				for _, element := range sqlrog.LintTables(schema) {
+					table := element.(*Table)
+					for _, column := range OrderedColumnFields(table.Fields) {
+						if column.Collate != "" && table.Collate != "" && column.Collate != table.Collate {
+							issues = append(issues, sqlrog.TableLintIssue(table, fmt.Sprintf("column %s uses %s collation, table uses %s", column.Name, column.Collate, table.Collate)))
+						}
+					}
+				}
+				return issues
+			},
+		},
+	}...)
+}
+
+// HasIndexOn checks if any index of the table starts with the given fields in the same order.
+func (t *Table) HasIndexOn(fields map[string]IndexField) bool {
+	required := OrderedIndexFields(fields)
+	for _, indexType := range []string{PRIMARY_KEY, UNIQUE, INDEX} {
+		for _, index := range t.Indexes[indexType] {
+			indexFields := OrderedIndexFields(index.Fields)
+			if indexFields == required || strings.HasPrefix(indexFields, required+",") {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+func (t *Table) HasPrimaryKey() bool {
+	return len(t.Indexes[PRIMARY_KEY]) > 0
+}
+
+func (t *Table) LintColumns() []*sqlrog.LintColumn {
+	var columns []*sqlrog.LintColumn
+	for _, column := range OrderedColumnFields(t.Fields) {
+		columns = append(columns, &sqlrog.LintColumn{Name: column.Name, Charset: column.Charset, NotNull: column.NotNull})
+	}
+
+	return columns
+}
+
+// LintCharsets guesses the database charset by the table charsets.
+func (t *Table) LintCharsets() []string {
+	return []string{t.Charset}
+}
+
+func (t *Table) LintUniqueIndexes() []*sqlrog.LintIndex {
+	var indexes []*sqlrog.LintIndex
+	for _, indexType := range []string{UNIQUE, INDEX} {
+		for _, index := range t.Indexes[indexType] {
+			if index.Unique {
+				indexes = append(indexes, &sqlrog.LintIndex{Name: index.Name, Fields: strings.Split(OrderedIndexFields(index.Fields), ",")})
+			}
+		}
+	}
+
+	return indexes
+}
+
+// LintChildren lets the naming rule check columns, indexes and triggers, primary keys are always named PRIMARY.
+func (t *Table) LintChildren() []*sqlrog.LintChild {
+	var children []*sqlrog.LintChild
+	for _, column := range OrderedColumnFields(t.Fields) {
+		children = append(children, &sqlrog.LintChild{Type: column.GetTypeName(), Name: column.Name})
+	}
+	for indexType, indexes := range t.Indexes {
+		if indexType == PRIMARY_KEY {
+			continue
+		}
+		for _, index := range indexes {
+			children = append(children, &sqlrog.LintChild{Type: index.GetTypeName(), Name: index.Name})
+		}
+	}
+	for _, trigger := range t.Triggers {
+		children = append(children, &sqlrog.LintChild{Type: trigger.GetTypeName(), Name: trigger.Name})
+	}
+
+	return children
+}
+
+func (p *Procedure) LintSource() string {
+	return p.Source
+}
+
+func (f *Function) LintSource() string {
+	return f.Source
+}
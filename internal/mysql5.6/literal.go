@@ -0,0 +1,25 @@
+package mysql
+
+import "strings"
+
+var (
+	literalReplacer          = strings.NewReplacer(`\`, `\\`, "'", "''")
+	noBackslashQuoteReplacer = strings.NewReplacer("'", "''")
+)
+
+// StringLiteral puts the value in single quotes, escaping quotes and backslashes.
+func StringLiteral(value string) string {
+	return "'" + literalReplacer.Replace(value) + "'"
+}
+
+// SqlModeStringLiteral escapes the value for a statement running in the sql_mode. Backslashes are regular
+// characters with NO_BACKSLASH_ESCAPES, so only quotes are doubled.
+func SqlModeStringLiteral(value string, sqlMode string) string {
+	for _, mode := range strings.Split(sqlMode, ",") {
+		if strings.EqualFold(strings.TrimSpace(mode), "NO_BACKSLASH_ESCAPES") {
+			return "'" + noBackslashQuoteReplacer.Replace(value) + "'"
+		}
+	}
+
+	return StringLiteral(value)
+}
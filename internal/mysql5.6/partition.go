@@ -0,0 +1,220 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/stpatrickw/sqlrog/internal/sqlrog"
+)
+
+const (
+	PARTITION_RANGE = "RANGE"
+	PARTITION_LIST  = "LIST"
+	MAXVALUE        = "MAXVALUE"
+)
+
+type Partitioning struct {
+	Method        string       `yaml:"method"`
+	Expression    string       `yaml:"expression"`
+	SubMethod     string       `yaml:"sub_method,omitempty"`
+	SubExpression string       `yaml:"sub_expression,omitempty"`
+	Subpartitions int          `yaml:"subpartitions,omitempty"`
+	Partitions    []*Partition `yaml:"partitions"`
+}
+
+type Partition struct {
+	Name        string `yaml:"name"`
+	Description string `yaml:"description,omitempty"`
+	Comment     string `yaml:"comment,omitempty"`
+	Position    int    `yaml:"position"`
+}
+
+// IsRanged tells if partitions are defined by values (RANGE, LIST and their COLUMNS variants)
+// and not only by their number (HASH, KEY).
+func (p *Partitioning) IsRanged() bool {
+	return strings.HasPrefix(p.Method, PARTITION_RANGE) || strings.HasPrefix(p.Method, PARTITION_LIST)
+}
+
+func (p *Partitioning) OrderedPartitions() []*Partition {
+	partitions := append([]*Partition{}, p.Partitions...)
+	sort.Slice(partitions, func(i, j int) bool {
+		return partitions[i].Position < partitions[j].Position
+	})
+
+	return partitions
+}
+
+func (p *Partitioning) Definition() string {
+	definition := fmt.Sprintf("PARTITION BY %s (%s)", p.Method, p.Expression)
+	if p.SubMethod != "" {
+		definition += fmt.Sprintf(" SUBPARTITION BY %s (%s)", p.SubMethod, p.SubExpression)
+		if p.Subpartitions > 0 {
+			definition += fmt.Sprintf(" SUBPARTITIONS %d", p.Subpartitions)
+		}
+	}
+
+	return fmt.Sprintf("%s (\n\t%s\n)", definition, p.PartitionsDefinition(p.OrderedPartitions(), ",\n\t"))
+}
+
+func (p *Partitioning) PartitionsDefinition(partitions []*Partition, joinSep string) string {
+	var definitions []string
+	for _, partition := range partitions {
+		definitions = append(definitions, p.PartitionDefinition(partition))
+	}
+
+	return strings.Join(definitions, joinSep)
+}
+
+func (p *Partitioning) PartitionDefinition(partition *Partition) string {
+	definition := "PARTITION " + QuoteIdentifier(partition.Name)
+	switch {
+	case p.Method == PARTITION_RANGE && partition.Description == MAXVALUE:
+		definition += " VALUES LESS THAN MAXVALUE"
+	case strings.HasPrefix(p.Method, PARTITION_RANGE):
+		definition += fmt.Sprintf(" VALUES LESS THAN (%s)", partition.Description)
+	case strings.HasPrefix(p.Method, PARTITION_LIST):
+		definition += fmt.Sprintf(" VALUES IN (%s)", partition.Description)
+	}
+	if partition.Comment != "" {
+		definition += " COMMENT = " + StringLiteral(partition.Comment)
+	}
+
+	return definition
+}
+
+func (p *Partitioning) SchemeEquals(other *Partitioning) bool {
+	return p.Method == other.Method && p.Expression == other.Expression && p.SubMethod == other.SubMethod &&
+		p.SubExpression == other.SubExpression && p.Subpartitions == other.Subpartitions
+}
+
+func PartitioningEquals(src *Partitioning, dest *Partitioning) bool {
+	if src == nil || dest == nil {
+		return src == nil && dest == nil
+	}
+	if !src.SchemeEquals(dest) || len(src.Partitions) != len(dest.Partitions) {
+		return false
+	}
+	destPartitions := dest.OrderedPartitions()
+	for i, partition := range src.OrderedPartitions() {
+		if !PartitionEquals(partition, destPartitions[i]) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func PartitionEquals(src *Partition, dest *Partition) bool {
+	return src.Name == dest.Name && src.Description == dest.Description && src.Comment == dest.Comment
+}
+
+// PartitioningAlterDefinition turns target partitioning of the table into the source one. Partitions missing
+// in source are dropped, partitions appended after the last target partition are added and the rest of the
+// changes reorganize target partitions starting from the first changed one.
+func (t *Table) PartitioningAlterDefinition(src *Partitioning, dest *Partitioning, sep string) []string {
+	if PartitioningEquals(src, dest) {
+		return nil
+	}
+	if src == nil {
+		return []string{fmt.Sprintf("ALTER TABLE %s REMOVE PARTITIONING%s", QuoteIdentifier(t.Name), sep)}
+	}
+	if dest == nil || !src.SchemeEquals(dest) {
+		return []string{fmt.Sprintf("ALTER TABLE %s %s%s", QuoteIdentifier(t.Name), src.Definition(), sep)}
+	}
+	if !src.IsRanged() {
+		if diff := len(src.Partitions) - len(dest.Partitions); diff > 0 {
+			return []string{fmt.Sprintf("ALTER TABLE %s ADD PARTITION PARTITIONS %d%s", QuoteIdentifier(t.Name), diff, sep)}
+		} else if diff < 0 {
+			return []string{fmt.Sprintf("ALTER TABLE %s COALESCE PARTITION %d%s", QuoteIdentifier(t.Name), -diff, sep)}
+		}
+		return nil
+	}
+
+	var definitions []string
+	srcPartitions := src.OrderedPartitions()
+	srcNames := make(map[string]bool)
+	for _, partition := range srcPartitions {
+		srcNames[partition.Name] = true
+	}
+	var (
+		dropped   []string
+		remaining []*Partition
+	)
+	for _, partition := range dest.OrderedPartitions() {
+		if srcNames[partition.Name] {
+			remaining = append(remaining, partition)
+		} else {
+			dropped = append(dropped, QuoteIdentifier(partition.Name))
+		}
+	}
+	if len(dropped) > 0 {
+		definitions = append(definitions, fmt.Sprintf("ALTER TABLE %s DROP PARTITION %s%s", QuoteIdentifier(t.Name), strings.Join(dropped, ", "), sep))
+	}
+	changed := len(remaining)
+	for i, partition := range remaining {
+		if i >= len(srcPartitions) || !PartitionEquals(srcPartitions[i], partition) {
+			changed = i
+			break
+		}
+	}
+	if changed == len(remaining) {
+		if changed < len(srcPartitions) {
+			definitions = append(definitions, fmt.Sprintf("ALTER TABLE %s ADD PARTITION (%s)%s",
+				QuoteIdentifier(t.Name), src.PartitionsDefinition(srcPartitions[changed:], ", "), sep))
+		}
+		return definitions
+	}
+	var names []string
+	for _, partition := range remaining[changed:] {
+		names = append(names, QuoteIdentifier(partition.Name))
+	}
+
+	return append(definitions, fmt.Sprintf("ALTER TABLE %s REORGANIZE PARTITION %s INTO (%s)%s",
+		QuoteIdentifier(t.Name), strings.Join(names, ", "), src.PartitionsDefinition(srcPartitions[changed:], ", "), sep))
+}
+
+func (p *Partition) FetchPartitionsFromDB(ctx context.Context, conn *sql.DB, filter *sqlrog.ElementFilter) (map[string]*Partitioning, error) {
+	partitionings := make(map[string]*Partitioning)
+
+	condition, args := FilterCondition(filter, "TABLE_NAME")
+	rows, err := conn.QueryContext(ctx, `
+		SELECT TABLE_NAME, PARTITION_NAME, PARTITION_ORDINAL_POSITION, coalesce(SUBPARTITION_ORDINAL_POSITION, 0),
+			PARTITION_METHOD, coalesce(PARTITION_EXPRESSION, ''), coalesce(SUBPARTITION_METHOD, ''),
+			coalesce(SUBPARTITION_EXPRESSION, ''), coalesce(PARTITION_DESCRIPTION, ''), PARTITION_COMMENT
+		FROM INFORMATION_SCHEMA.PARTITIONS
+		WHERE TABLE_SCHEMA = schema() AND PARTITION_NAME IS NOT NULL`+condition+`
+		ORDER BY TABLE_NAME, PARTITION_ORDINAL_POSITION, SUBPARTITION_ORDINAL_POSITION`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var (
+			tableName            string
+			subpartitionPosition int
+		)
+		partitioning := &Partitioning{}
+		partition := &Partition{}
+		err := rows.Scan(&tableName, &partition.Name, &partition.Position, &subpartitionPosition,
+			&partitioning.Method, &partitioning.Expression, &partitioning.SubMethod,
+			&partitioning.SubExpression, &partition.Description, &partition.Comment)
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := partitionings[tableName]; !ok {
+			partitionings[tableName] = partitioning
+		}
+		partitioning = partitionings[tableName]
+		if subpartitionPosition > partitioning.Subpartitions {
+			partitioning.Subpartitions = subpartitionPosition
+		}
+		if subpartitionPosition <= 1 {
+			partitioning.Partitions = append(partitioning.Partitions, partition)
+		}
+	}
+
+	return partitionings, rows.Err()
+}
@@ -2,6 +2,7 @@ package mysql
 
 import (
 	"bytes"
+	"context"
 	"database/sql"
 	"fmt"
 	"github.com/stpatrickw/sqlrog/internal/sqlrog"
@@ -20,6 +21,7 @@ type Procedure struct {
 	InputParameters          map[string]*ProcedureParameter `yaml:"input_params"`
 	OutputParameters         map[string]*ProcedureParameter `yaml:"output_params"`
 	Deterministic            bool
+	RoutineCharacteristics   `yaml:",inline"`
 }
 
 type ProcedureParameter struct {
@@ -43,23 +45,23 @@ func (p *Procedure) GetPluralTypeName() string {
 }
 
 func (p *Procedure) AlterDefinition(other interface{}, sep string) []string {
-	return append(p.CastType(other).DropDefinition(sep), p.CastType(other).CreateDefinition(sep)...)
+	return append(p.DropDefinition(sep), p.CreateDefinition(sep)...)
 }
 
 func (p *Procedure) CreateDefinition(sep string) []string {
-	return []string{fmt.Sprintf("CREATE %s", p.Definition(sep))}
+	return p.WithSqlMode([]string{fmt.Sprintf("CREATE %s", p.Definition(sep))}, sep)
 }
 
 func (p *Procedure) DropDefinition(sep string) []string {
-	return []string{fmt.Sprintf("DROP PROCEDURE IF EXISTS %s%s", p.Name, sep)}
+	return []string{fmt.Sprintf("DROP PROCEDURE IF EXISTS %s%s", QuoteIdentifier(p.Name), sep)}
 }
 
 func (p *Procedure) Definition(sep string) string {
-	procTmpl, err := template.New("procedure").Parse(`PROCEDURE ` + "{{ .Name}}" + `({{if .InputParameters}}
+	procTmpl, err := template.New("procedure").Funcs(templateFuncs).Parse(`{{if .Definer}}DEFINER={{ .DefinerDefinition}} {{end}}PROCEDURE ` + "{{ quote .Name}}" + `({{if .InputParameters}}
 	{{$first := true}}{{range $index, $element := .InputParameters}}{{if $first}}{{$first = false}}{{else}},
-	{{end}}IN {{.Name}} {{.TypeName}}{{if ne .Charset ""}} CHARACTER SET {{.Charset}}{{end}}{{if ne .Collate ""}} COLLATE {{.Collate}}{{end}}{{end}}{{end}}{{if .OutputParameters}}{{if .InputParameters}},{{end}}
+	{{end}}IN {{quote .Name}} {{.TypeName}}{{if ne .Charset ""}} CHARACTER SET {{.Charset}}{{end}}{{if ne .Collate ""}} COLLATE {{.Collate}}{{end}}{{end}}{{end}}{{if .OutputParameters}}{{if .InputParameters}},{{end}}
 	{{$first := true}}{{range $index, $element := .OutputParameters}}{{if $first}}{{$first = false}}{{else}},
-	{{end}}OUT {{.Name}} {{.TypeName}}{{if ne .Charset ""}} CHARACTER SET {{.Charset}}{{end}}{{if ne .Collate ""}} COLLATE {{.Collate}}{{end}}{{end}}{{end}}){{if .Deterministic}} DETERMINISTIC{{end}}
+	{{end}}OUT {{quote .Name}} {{.TypeName}}{{if ne .Charset ""}} CHARACTER SET {{.Charset}}{{end}}{{if ne .Collate ""}} COLLATE {{.Collate}}{{end}}{{end}}{{end}}){{if .Deterministic}} DETERMINISTIC{{end}}{{ .CharacteristicsDefinition}}
 {{ .Source }}`)
 
 	if err != nil {
@@ -78,7 +80,8 @@ func (p *Procedure) Definition(sep string) string {
 func (p *Procedure) Equals(e2 interface{}) bool {
 	other := p.CastType(e2)
 
-	if p.Name != other.Name || p.Source != other.Source || p.Deterministic != other.Deterministic {
+	if p.Name != other.Name || p.Source != other.Source || p.Deterministic != other.Deterministic ||
+		p.RoutineCharacteristics != other.RoutineCharacteristics {
 		return false
 	}
 
@@ -138,20 +141,23 @@ func (p *Procedure) CastType(other interface{}) *Procedure {
 	return other.(*Procedure)
 }
 
-func (p *Procedure) FetchElementsFromDB(conn *sql.DB) ([]sqlrog.ElementSchema, error) {
+func (p *Procedure) FetchElementsFromDB(ctx context.Context, conn *sql.DB, filter *sqlrog.ElementFilter) ([]sqlrog.ElementSchema, error) {
 	var procedures []sqlrog.ElementSchema
 
-	rows, err := conn.Query(`SELECT SPECIFIC_NAME, ROUTINE_DEFINITION, IS_DETERMINISTIC 
-		FROM information_schema.routines WHERE routine_schema = schema() and routine_type = 'PROCEDURE' order by 1`)
+	condition, args := FilterCondition(filter, "SPECIFIC_NAME")
+
+	rows, err := conn.QueryContext(ctx, `SELECT SPECIFIC_NAME, ROUTINE_DEFINITION, IS_DETERMINISTIC,
+			DEFINER, SECURITY_TYPE, SQL_DATA_ACCESS, SQL_MODE, ROUTINE_COMMENT
+		FROM information_schema.routines WHERE routine_schema = schema() and routine_type = 'PROCEDURE'`+condition+` order by 1`, args...)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
 	parametersQuery := `SELECT SPECIFIC_NAME, PARAMETER_NAME, PARAMETER_MODE, DTD_IDENTIFIER, CHARACTER_SET_NAME, COLLATION_NAME, ORDINAL_POSITION 
 		FROM information_schema.parameters
-		WHERE SPECIFIC_SCHEMA = schema() AND ROUTINE_TYPE = 'PROCEDURE';`
+		WHERE SPECIFIC_SCHEMA = schema() AND ROUTINE_TYPE = 'PROCEDURE'`
 
-	parameterRows, err := conn.Query(parametersQuery)
+	parameterRows, err := conn.QueryContext(ctx, parametersQuery+condition, args...)
 	if err != nil {
 		return procedures, err
 	}
@@ -182,7 +188,8 @@ func (p *Procedure) FetchElementsFromDB(conn *sql.DB) ([]sqlrog.ElementSchema, e
 	for rows.Next() {
 		var deterministic string
 		procedure := &Procedure{InputParameters: make(map[string]*ProcedureParameter), OutputParameters: make(map[string]*ProcedureParameter)}
-		err := rows.Scan(&procedure.Name, &procedure.Source, &deterministic)
+		err := rows.Scan(&procedure.Name, &procedure.Source, &deterministic, &procedure.Definer, &procedure.SqlSecurity,
+			&procedure.DataAccess, &procedure.SqlMode, &procedure.Comment)
 		if err != nil {
 			return nil, err
 		}
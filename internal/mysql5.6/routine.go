@@ -0,0 +1,84 @@
+package mysql
+
+import (
+	"fmt"
+	"strings"
+)
+
+// RoutineCharacteristics keeps attributes which procedures and functions get on creation.
+type RoutineCharacteristics struct {
+	Definer     string `yaml:"definer,omitempty"`
+	SqlSecurity string `yaml:"sql_security,omitempty"`
+	DataAccess  string `yaml:"data_access,omitempty"`
+	SqlMode     string `yaml:"sql_mode,omitempty"`
+	Comment     string `yaml:"comment,omitempty"`
+}
+
+func (rc RoutineCharacteristics) DefinerDefinition() string {
+	return DefinerDefinition(rc.Definer)
+}
+
+// CharacteristicsDefinition returns characteristics which go after routine parameters.
+func (rc RoutineCharacteristics) CharacteristicsDefinition() string {
+	var definition string
+	if rc.Comment != "" {
+		// the CREATE statement runs in the routine sql_mode, see WithSqlMode
+		definition += " COMMENT " + SqlModeStringLiteral(rc.Comment, rc.SqlMode)
+	}
+	if rc.DataAccess != "" {
+		definition += " " + rc.DataAccess
+	}
+	if rc.SqlSecurity != "" {
+		definition += " SQL SECURITY " + rc.SqlSecurity
+	}
+
+	return definition
+}
+
+// WithSqlMode wraps statements creating the routine into the sql_mode it was created with,
+// the session sql_mode is restored afterwards.
+func (rc RoutineCharacteristics) WithSqlMode(statements []string, sep string) []string {
+	if rc.SqlMode == "" {
+		return statements
+	}
+	definitions := []string{
+		"SET @sqlrog_sql_mode = @@SESSION.sql_mode" + sep,
+		fmt.Sprintf("SET SESSION sql_mode = %s%s", StringLiteral(rc.SqlMode), sep),
+	}
+	definitions = append(definitions, statements...)
+
+	return append(definitions, "SET SESSION sql_mode = @sqlrog_sql_mode"+sep)
+}
+
+// DefinerDefinition turns user@host definer into `user`@`host` account.
+func DefinerDefinition(definer string) string {
+	user, host := definer, "%"
+	if i := strings.LastIndex(definer, "@"); i >= 0 {
+		user, host = definer[:i], definer[i+1:]
+	}
+
+	return backtickedName(user) + "@" + backtickedName(host)
+}
+
+func backtickedName(name string) string {
+	return "`" + strings.Replace(name, "`", "``", -1) + "`"
+}
+
+// RewriteDefiner sets the definer of views, routines and events, so environments
+// with different accounts can share the same project.
+func (mys *MysqlSchema) RewriteDefiner(definer string) {
+	for _, elements := range mys.CoreElements {
+		for _, element := range elements {
+			switch el := element.(type) {
+			case *View:
+				el.Definer = definer
+			case *Procedure:
+				el.Definer = definer
+			case *Function:
+				el.Definer = definer
+			case *Event:
+				el.Definer = definer
+			}
+		}
+	}
+}
@@ -1,10 +1,12 @@
 package mysql
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
 	"os"
 	"reflect"
+	"regexp"
 	"sort"
 
 	_ "github.com/go-sql-driver/mysql"
@@ -63,7 +65,7 @@ func (my *MysqlEngine) CreateParams() interface{} {
 	return &MysqlParams{}
 }
 
-func (my *MysqlEngine) LoadSchema(config *sqlrog.Config, reader sqlrog.ObjectReader) (sqlrog.ElementSchema, error) {
+func (my *MysqlEngine) LoadSchema(ctx context.Context, config *sqlrog.Config, reader sqlrog.ObjectReader, filter *sqlrog.ElementFilter) (sqlrog.ElementSchema, error) {
 	schema := &MysqlSchema{
 		sqlrog.BaseElementSchema{
 			CoreElements: make(map[string]map[string]sqlrog.ElementSchema),
@@ -76,22 +78,36 @@ func (my *MysqlEngine) LoadSchema(config *sqlrog.Config, reader sqlrog.ObjectRea
 			return nil, errors.New(fmt.Sprintf("Folder for Project: %s doesn't exist", config.ProjectName))
 		}
 
-		elements, err := my.LoadElementsFromFiles(config.ProjectName, schema, reader)
+		elements, err := my.LoadElementsFromFiles(config.ProjectName, schema, reader, filter)
 		if err != nil {
 			return nil, err
 		}
 		schemaElements = append(schemaElements, elements...)
+		if config.Grants {
+			grants, err := my.LoadOptionalElementTypeFromFiles(config.ProjectName, &Grant{}, reader, filter)
+			if err != nil {
+				return nil, err
+			}
+			schemaElements = append(schemaElements, grants...)
+		}
 
 	} else {
 		conn, err := my.OpenConnection(config.Params.(*MysqlParams))
 		if err != nil {
 			return nil, err
 		}
-		elements, err := schema.FetchElementsFromDB(conn)
+		elements, err := schema.FetchElementsFromDB(ctx, conn, filter)
 		if err != nil {
 			return nil, err
 		}
 		schemaElements = append(schemaElements, elements...)
+		if config.Grants {
+			grants, err := (&Grant{}).FetchElementsFromDB(ctx, conn, filter)
+			if err != nil {
+				return nil, err
+			}
+			schemaElements = append(schemaElements, grants...)
+		}
 
 		my.CloseConnection(conn)
 	}
@@ -103,24 +119,80 @@ func (my *MysqlEngine) LoadSchema(config *sqlrog.Config, reader sqlrog.ObjectRea
 		}
 	}
 
+	if config.AppType == sqlrog.ProjectTypeFile {
+		schema.ParseColumnsExtra()
+	}
+	if !config.AutoIncrement {
+		schema.ResetAutoIncrement()
+	}
+	if config.Definer != "" {
+		schema.RewriteDefiner(config.Definer)
+	}
+
+	if config.AppType == sqlrog.ProjectTypeFile {
+		schema.TrackData(config)
+	} else if len(config.Data) > 0 {
+		conn, err := my.OpenConnection(config.Params.(*MysqlParams))
+		if err != nil {
+			return nil, err
+		}
+		err = schema.FetchDataFromDB(ctx, conn, config)
+		my.CloseConnection(conn)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	return schema, nil
 }
 
-func (my *MysqlEngine) ExecuteSQL(config *sqlrog.Config, sqls []string) error {
+func (my *MysqlEngine) ExecuteSQL(ctx context.Context, config *sqlrog.Config, sqls []string) error {
 	conn, err := my.OpenConnection(config.Params.(*MysqlParams))
 	if err != nil {
 		return err
 	}
 	defer my.CloseConnection(conn)
-	for _, stmt := range sqls {
-		_, err = conn.Exec(stmt)
+	// session variables like sql_mode have to survive between statements
+	session, err := conn.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer session.Close()
+	for i, stmt := range sqls {
+		// a cancelled run stops before the next statement, so long batches like data diffs can be interrupted
+		if err := ctx.Err(); err != nil {
+			return &sqlrog.StatementError{Index: i, Err: err}
+		}
+		_, err = session.ExecContext(ctx, stmt)
 		if err != nil {
-			return err
+			return &sqlrog.StatementError{Index: i, Err: err}
 		}
 	}
 	return nil
 }
 
+// ScriptRenderer switches the delimiter of the mysql client for routines, triggers and events.
+func (my *MysqlEngine) ScriptRenderer() *sqlrog.ScriptRenderer {
+	return &sqlrog.ScriptRenderer{
+		Delimiter:         sqlrog.DEFAULT_SQL_SEP,
+		CompoundDelimiter: "//",
+		SetDelimiter: func(delimiter string, current string) string {
+			return "DELIMITER " + delimiter
+		},
+	}
+}
+
+// ScriptParser reads scripts of the mysql client with DELIMITER commands.
+func (my *MysqlEngine) ScriptParser() *sqlrog.ScriptParser {
+	return &sqlrog.ScriptParser{
+		Delimiter:        sqlrog.DEFAULT_SQL_SEP,
+		Quotes:           "'\"`",
+		BackslashEscapes: true,
+		LineComments:     []string{"--", "#"},
+		DelimiterLine:    regexp.MustCompile(`(?i)^DELIMITER[ \t]+(\S+)`),
+	}
+}
+
 func (my *MysqlEngine) OpenConnection(params *MysqlParams) (*sql.DB, error) {
 	connectionString := fmt.Sprintf("%s:%s@tcp(%s:%s)/%s",
 		params.GetParam("User"),
@@ -143,6 +215,10 @@ func (my *MysqlEngine) SchemaDiff(source interface{}, target interface{}) []*sql
 	for _, el := range sourceSchema.GetGlobalChildElements() {
 		changes = append(changes, my.CompareScheme(sourceSchema.CoreElements[el.GetTypeName()], targetSchema.CoreElements[el.GetTypeName()])...)
 	}
+	changes = append(changes, my.CompareScheme(sourceSchema.CoreElements[CORE_ELEMENT_GRANT_NAME], targetSchema.CoreElements[CORE_ELEMENT_GRANT_NAME])...)
+	if dataDiff := my.DataDiff(sourceSchema, targetSchema); dataDiff != nil {
+		changes = append(changes, dataDiff)
+	}
 	sort.Slice(changes, func(i, j int) bool {
 		return changes[i].Priority > changes[j].Priority
 	})
@@ -165,7 +241,7 @@ func (mys *MysqlSchema) GetChilds() []sqlrog.ElementSchema {
 }
 
 func (mys *MysqlSchema) GetGlobalChildElements() []sqlrog.ElementSchema {
-	return []sqlrog.ElementSchema{&Table{}, &View{}, &Function{}, &Procedure{}}
+	return []sqlrog.ElementSchema{&Table{}, &View{}, &Function{}, &Procedure{}, &Event{}}
 }
 
 func (mys *MysqlSchema) AddChild(child sqlrog.ElementSchema) error {
@@ -177,10 +253,10 @@ func (mys *MysqlSchema) AddChild(child sqlrog.ElementSchema) error {
 	return nil
 }
 
-func (mys *MysqlSchema) FetchElementsFromDB(conn *sql.DB) ([]sqlrog.ElementSchema, error) {
+func (mys *MysqlSchema) FetchElementsFromDB(ctx context.Context, conn *sql.DB, filter *sqlrog.ElementFilter) ([]sqlrog.ElementSchema, error) {
 	var elements []sqlrog.ElementSchema
 	for _, el := range mys.GetGlobalChildElements() {
-		fetchedElements, err := el.FetchElementsFromDB(conn)
+		fetchedElements, err := el.FetchElementsFromDB(ctx, conn, filter)
 		if err != nil {
 			return nil, err
 		}
@@ -192,3 +268,7 @@ func (mys *MysqlSchema) FetchElementsFromDB(conn *sql.DB) ([]sqlrog.ElementSchem
 func (mys *MysqlSchema) String() string {
 	return "schema"
 }
+
+func FilterCondition(filter *sqlrog.ElementFilter, column string) (string, []interface{}) {
+	return filter.Condition(column, "LOCATE(UPPER(?), UPPER(%s)) > 0", "LOCATE(UPPER(?), UPPER(%s)) = 0")
+}
@@ -1,8 +1,12 @@
 package mysql
 
 import (
+	"context"
 	"fmt"
+	"io/ioutil"
 	"os"
+	"path/filepath"
+	"sort"
 	"strings"
 	"testing"
 
@@ -27,7 +31,7 @@ func setUp() {
 	sourceConfig = sqlrog.Config{
 		ProjectName: "test_db",
 		Engine:      "mysql5.6",
-		AppType:     "project",
+		AppType:     "file",
 		Params: sqlrog.ConfigParams{
 			FileType: "yml",
 		},
@@ -36,13 +40,13 @@ func setUp() {
 }
 
 func reloadSchemas() {
-	schema, err := myEngine.LoadSchema(&sourceConfig, &sqlrog.YamlSchemaReader{})
+	schema, err := myEngine.LoadSchema(context.Background(), &sourceConfig, &sqlrog.YamlSchemaReader{}, nil)
 	if err != nil {
 		fmt.Println(err)
 		os.Exit(1)
 	}
 	sourceSchema = schema
-	schema, err = myEngine.LoadSchema(&sourceConfig, &sqlrog.YamlSchemaReader{})
+	schema, err = myEngine.LoadSchema(context.Background(), &sourceConfig, &sqlrog.YamlSchemaReader{}, nil)
 	if err != nil {
 		fmt.Println(err)
 		os.Exit(1)
@@ -55,7 +59,7 @@ func TestTableCreateSQL(t *testing.T) {
 
 	expectedSqls := []string{
 		`CREATE TABLE categories (
-	id int(11) NOT NULL auto_increment,
+	id int(11) NOT NULL AUTO_INCREMENT,
 	serial int(11) NOT NULL,
 	color varchar(45) CHARACTER SET latin1 COLLATE latin1_swedish_ci,
 	colorname varchar(45) CHARACTER SET utf8 COLLATE utf8_unicode_ci NOT NULL,
@@ -64,7 +68,7 @@ func TestTableCreateSQL(t *testing.T) {
 	PRIMARY KEY(id,serial)
 ) Engine=InnoDB CHARSET=latin1;`,
 		`CREATE TABLE cars (
-	id int(11) NOT NULL auto_increment,
+	id int(11) NOT NULL AUTO_INCREMENT,
 	name varchar(45) CHARACTER SET latin1 COLLATE latin1_swedish_ci DEFAULT 'no name',
 	id_category int(11) NOT NULL,
 	speed int(11) COMMENT 'describes speed',
@@ -74,31 +78,31 @@ func TestTableCreateSQL(t *testing.T) {
 	PRIMARY KEY(id)
 ) Engine=InnoDB CHARSET=latin1;`,
 		`CREATE TABLE engines (
-	id int(11) NOT NULL auto_increment,
+	id int(11) NOT NULL AUTO_INCREMENT,
 	name varchar(45) CHARACTER SET latin1 COLLATE latin1_swedish_ci,
 	PRIMARY KEY(id)
 ) Engine=InnoDB CHARSET=latin1;`,
 		`CREATE TABLE producers (
-	id int(11) NOT NULL auto_increment,
+	id int(11) NOT NULL AUTO_INCREMENT,
 	name varchar(45) CHARACTER SET latin1 COLLATE latin1_swedish_ci,
 	PRIMARY KEY(id)
 ) Engine=InnoDB CHARSET=latin1;`,
-		`CREATE INDEX fk_cars1_idx ON cars (id_category,serial);`,
-		`CREATE INDEX idx_2 ON cars (speed,weight);`,
+		`CREATE INDEX fk_cars1_idx ON cars (id_category,serial) USING BTREE;`,
+		`CREATE INDEX idx_2 ON cars (speed,weight) USING BTREE;`,
 		`ALTER TABLE cars ADD CONSTRAINT fk_cars1 FOREIGN KEY (id_category,serial) REFERENCES categories (id,serial) ON DELETE NO ACTION ON UPDATE NO ACTION;`,
 		`CREATE TRIGGER cars_BEFORE_INSERT BEFORE INSERT ON cars FOR EACH ROW
 BEGIN
 	SET NEW.weight = 18;
 END;`,
-		`CREATE INDEX idx_1 ON cars (name);`,
-		`CREATE INDEX fk_categories2_idx ON categories (id_producer);`,
-		`CREATE INDEX fk_categories1_idx ON categories (id_engine);`,
+		`CREATE INDEX idx_1 ON cars (name) USING BTREE;`,
+		`CREATE INDEX fk_categories2_idx ON categories (id_producer) USING BTREE;`,
+		`CREATE INDEX fk_categories1_idx ON categories (id_engine) USING BTREE;`,
 		`ALTER TABLE categories ADD CONSTRAINT fk_categories1 FOREIGN KEY (id_engine) REFERENCES engines (id) ON DELETE NO ACTION ON UPDATE NO ACTION;`,
 		`ALTER TABLE categories ADD CONSTRAINT fk_categories2 FOREIGN KEY (id_producer) REFERENCES producers (id) ON DELETE CASCADE ON UPDATE CASCADE;`,
-		`ALTER TABLE cars ADD CONSTRAINT serial_UNIQUE UNIQUE (serial);`,
+		`ALTER TABLE cars ADD CONSTRAINT serial_UNIQUE UNIQUE (serial) USING BTREE;`,
 		`CREATE OR REPLACE VIEW cars_view 
 as select * from cars;`,
-		`CREATE FUNCTION 1plus(
+		`CREATE FUNCTION ` + "`1plus`" + `(
 	arg2 int(11)) RETURNS int(11)
 BEGIN
 
@@ -113,6 +117,12 @@ BEGIN
 	changes := myEngine.SchemaDiff(sourceSchema, targetSchema)
 
 	for _, diff := range changes {
+		switch diff.To.(type) {
+		case *Index, *Trigger:
+			if !diff.SqlOnly {
+				t.Errorf("Expected %s %s to be saved in its table file\n", diff.Type, diff.To.GetName())
+			}
+		}
 		for _, diffSql := range diff.DiffSql(";") {
 			for index, sql := range expectedSqls {
 				if diffSql == sql {
@@ -163,3 +173,670 @@ func TestTableAlterSQL(t *testing.T) {
 		t.Errorf("Expected update table sql is not equal to real: \n%s\n%s\n", expectedSQL, sql[0])
 	}
 }
+
+func TestLoadSchemaWithFilter(t *testing.T) {
+	filter := &sqlrog.ElementFilter{
+		Match:  "car",
+		Ignore: []string{"view", "color"},
+	}
+	schema, err := myEngine.LoadSchema(context.Background(), &sourceConfig, &sqlrog.YamlSchemaReader{}, filter)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, element := range schema.GetChilds() {
+		names = append(names, element.GetName())
+	}
+	if len(names) != 1 || names[0] != "cars" {
+		t.Errorf("Expected only cars table to be loaded, got: %v\n", names)
+	}
+}
+
+func TestValidateSchema(t *testing.T) {
+	validationErrors, err := myEngine.ValidateSchema(context.Background(), &sourceConfig, &sqlrog.YamlSchemaReader{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(validationErrors) > 0 {
+		t.Errorf("Expected test project to be valid, got: %v\n", validationErrors)
+	}
+}
+
+func TestValidateSchemaWithBrokenFiles(t *testing.T) {
+	dir, err := ioutil.TempDir(".", "validate_db")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	if err = os.Mkdir(dir+"/tables", 0755); err != nil {
+		t.Fatal(err)
+	}
+	cars, err := ioutil.ReadFile("test_db/tables/cars.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// cars refer to categories which are missing, so table errors are reported next to the broken file
+	if err = ioutil.WriteFile(dir+"/tables/cars.yaml", cars, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err = ioutil.WriteFile(dir+"/tables/broken.yaml", []byte("name: [broken\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	config := sourceConfig
+	config.ProjectName = filepath.Base(dir)
+	validationErrors, err := myEngine.ValidateSchema(context.Background(), &config, &sqlrog.YamlSchemaReader{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var messages []string
+	for _, validationError := range validationErrors {
+		messages = append(messages, validationError.Error())
+	}
+	output := strings.Join(messages, "\n")
+	for _, expected := range []string{
+		"tables/broken.yaml: invalid yaml",
+		"tables/cars.yaml: FOREIGN KEY 'fk_cars1' refers to unknown table 'categories'",
+	} {
+		if !strings.Contains(output, expected) {
+			t.Errorf("Expected validation error is missing: %s\n%s\n", expected, output)
+		}
+	}
+}
+
+func TestTableValidate(t *testing.T) {
+	reloadSchemas()
+	tables := sourceSchema.(*MysqlSchema).CoreElements["table"]
+	cars := tables["cars"].(*Table)
+	cars.Indexes[INDEX]["idx_1"].Fields["unknown"] = IndexField{Name: "unknown", Position: 2}
+	cars.Indexes[FOREIGN_KEY]["fk_cars1"].SourceTable = "missing"
+	cars.Fields["weight"].Position = 1
+
+	expectedMessages := []string{
+		"INDEX 'idx_1' refers to unknown column 'unknown'",
+		"FOREIGN KEY 'fk_cars1' refers to unknown table 'missing'",
+		"columns 'id' and 'weight' have the same position 1",
+	}
+	messages := strings.Join(cars.Validate(tables), "\n")
+	for _, expected := range expectedMessages {
+		if !strings.Contains(messages, expected) {
+			t.Errorf("Expected validation message is missing: %s\n%s\n", expected, messages)
+		}
+	}
+}
+
+func TestLintSchema(t *testing.T) {
+	reloadSchemas()
+	config := &sqlrog.LintConfig{
+		Severities: map[string]string{"mixed_collations": sqlrog.LINT_SEVERITY_IGNORE},
+		Naming: map[string]string{
+			"procedure":    "^[a-z_]+$",
+			"table_column": "^[a-z_]+$",
+			"index":        "^(idx|fk)_",
+			"trigger":      "^trg_",
+		},
+	}
+	issues, err := sqlrog.LintSchema(myEngine.GetLintRules(), sourceSchema, config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var lines []string
+	for _, issue := range issues {
+		lines = append(lines, issue.String())
+	}
+	output := strings.Join(lines, "\n")
+	expectedIssues := []string{
+		"[warning] procedure GetAllCarsByColor: name doesn't match '^[a-z_]+$' (naming_convention)",
+		"[warning] procedure GetAllCarsByColor: source uses SELECT * (select_star)",
+		"[warning] index cars.serial_UNIQUE: name doesn't match '^(idx|fk)_' (naming_convention)",
+		"[warning] trigger cars.cars_BEFORE_INSERT: name doesn't match '^trg_' (naming_convention)",
+		"[warning] table categories: column colorname uses utf8 charset in latin1 database (column_charset)",
+	}
+	for _, expected := range expectedIssues {
+		if !strings.Contains(output, expected) {
+			t.Errorf("Expected lint issue is missing: %s\n%s\n", expected, output)
+		}
+	}
+	if strings.Contains(output, "mixed_collations") || strings.Contains(output, "no_primary_key") ||
+		strings.Contains(output, "table_column") || strings.Contains(output, "PRIMARY") {
+		t.Errorf("Unexpected lint issues:\n%s\n", output)
+	}
+	invalid := &sqlrog.LintConfig{Naming: map[string]string{"table": "^[a-z"}}
+	if _, err := sqlrog.LintSchema(myEngine.GetLintRules(), sourceSchema, invalid); err == nil {
+		t.Errorf("Expected invalid naming pattern to be a config error\n")
+	}
+}
+
+func TestTableDataDiff(t *testing.T) {
+	reloadSchemas()
+	value := func(s string) *string {
+		return &s
+	}
+	// generated columns can't be written, so their values of older project files are skipped
+	for _, schema := range []sqlrog.ElementSchema{sourceSchema, targetSchema} {
+		schema.(*MysqlSchema).CoreElements["table"]["engines"].(*Table).Fields["label"] = &TableColumn{
+			Name:                 "label",
+			Type:                 "varchar(100)",
+			Generated:            "STORED",
+			GenerationExpression: "upper(`name`)",
+			Position:             10,
+		}
+	}
+	sourceSchema.(*MysqlSchema).CoreElements["table"]["engines"].(*Table).Data = sqlrog.DataRows{
+		"1": {"id": value("1"), "name": value("V8"), "label": value("V8")},
+		"3": {"id": value("3"), "name": value("it's"), "label": value("IT'S")},
+		"4": {"id": value("4"), "name": value("V12"), "label": value("new")},
+	}
+	targetSchema.(*MysqlSchema).CoreElements["table"]["engines"].(*Table).Data = sqlrog.DataRows{
+		"1": {"id": value("1"), "name": value("V6"), "label": value("V6")},
+		"2": {"id": value("2"), "name": nil},
+		"4": {"id": value("4"), "name": value("V12"), "label": value("V12")},
+	}
+	var dataDiff *sqlrog.DiffObject
+	for _, change := range myEngine.SchemaDiff(sourceSchema, targetSchema) {
+		if change.Type == sqlrog.CORE_ELEMENT_TABLE_DATA_NAME {
+			dataDiff = change
+		}
+	}
+	if dataDiff == nil {
+		t.Fatal("Expected data diff is missing for table: engines")
+	}
+	expectedSqls := []string{
+		"DELETE FROM engines WHERE id = '2';",
+		"UPDATE engines SET name = 'V8' WHERE id = '1';",
+		"INSERT INTO engines (id,name) VALUES ('3','it''s');",
+	}
+	sqls := dataDiff.DiffSql(sqlrog.DEFAULT_SQL_SEP)
+	if strings.Join(sqls, "\n") != strings.Join(expectedSqls, "\n") {
+		t.Errorf("Expected data sql is not equal to real: \n%s\n%s\n", strings.Join(expectedSqls, "\n"), strings.Join(sqls, "\n"))
+	}
+}
+
+func TestGrantDiff(t *testing.T) {
+	schema, err := myEngine.LoadSchema(context.Background(), sourceConfig.WithGrants(true), &sqlrog.YamlSchemaReader{}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	grant, ok := schema.(*MysqlSchema).CoreElements[CORE_ELEMENT_GRANT_NAME]["app@localhost"].(*Grant)
+	if !ok {
+		t.Fatal("Expected grant is missing for: app@localhost")
+	}
+	for _, element := range sourceSchema.GetChilds() {
+		if element.GetTypeName() == CORE_ELEMENT_GRANT_NAME {
+			t.Fatal("Grants should not be loaded when project doesn't track them")
+		}
+	}
+	other := &Grant{
+		Grantee: grant.Grantee,
+		Privileges: []*sqlrog.Privilege{
+			{Privilege: "SELECT", ObjectType: GRANT_OBJECT_TABLE, Object: "cars"},
+			{Privilege: "DELETE", ObjectType: GRANT_OBJECT_TABLE, Object: "cars"},
+			{Privilege: "UPDATE", ObjectType: GRANT_OBJECT_TABLE, Object: "cars", Column: "name"},
+		},
+	}
+	expectedSqls := []string{
+		"REVOKE DELETE ON TABLE cars FROM 'app'@'localhost';",
+		"REVOKE UPDATE (name) ON TABLE cars FROM 'app'@'localhost';",
+		"GRANT EXECUTE ON PROCEDURE GetAllCarsByColor TO 'app'@'localhost';",
+		"GRANT UPDATE (name) ON TABLE cars TO 'app'@'localhost' WITH GRANT OPTION;",
+	}
+	diff := grant.Diff(other)
+	if diff == nil {
+		t.Fatal("Expected grant diff is missing")
+	}
+	sqls := diff.DiffSql(sqlrog.DEFAULT_SQL_SEP)
+	if strings.Join(sqls, "\n") != strings.Join(expectedSqls, "\n") {
+		t.Errorf("Expected grant sql is not equal to real: \n%s\n%s\n", strings.Join(expectedSqls, "\n"), strings.Join(sqls, "\n"))
+	}
+}
+
+func TestEventAlterSQL(t *testing.T) {
+	reloadSchemas()
+	event := sourceSchema.(*MysqlSchema).CoreElements[CORE_ELEMENT_EVENT_NAME]["purge_unnamed"].(*Event)
+	event.Status = "DISABLED"
+	event.IntervalValue = "1:30"
+	event.IntervalField = "HOUR_MINUTE"
+	changes := myEngine.SchemaDiff(sourceSchema, targetSchema)
+	if len(changes) != 1 || changes[0].Type != CORE_ELEMENT_EVENT_NAME {
+		t.Fatalf("Expected update event diff is missing for event: %s\n", event.Name)
+	}
+	expectedSQL := "ALTER DEFINER=`root`@`localhost` EVENT purge_unnamed\n" +
+		"ON SCHEDULE EVERY '1:30' HOUR_MINUTE STARTS '2020-01-01 00:00:00'\n" +
+		"ON COMPLETION PRESERVE\n" +
+		"DISABLE\n" +
+		"COMMENT 'removes cars without name'\n" +
+		"DO DELETE FROM cars WHERE name IS NULL;"
+	sql := changes[0].DiffSql(sqlrog.DEFAULT_SQL_SEP)
+	if sql[0] != expectedSQL {
+		t.Errorf("Expected update event sql is not equal to real: \n%s\n%s\n", expectedSQL, sql[0])
+	}
+}
+
+func TestTablePartitioningAlterSQL(t *testing.T) {
+	reloadSchemas()
+	monthly := func(names ...string) *Partitioning {
+		partitioning := &Partitioning{Method: "RANGE", Expression: "to_days(created)"}
+		for i, name := range names {
+			description := MAXVALUE
+			if name != "pmax" {
+				description = fmt.Sprintf("to_days('%s-%s-01')", name[1:5], name[5:])
+			}
+			partitioning.Partitions = append(partitioning.Partitions, &Partition{Name: name, Description: description, Position: i + 1})
+		}
+		return partitioning
+	}
+	cases := []struct {
+		source      *Partitioning
+		target      *Partitioning
+		expectedSql string
+	}{
+		{
+			source:      monthly("p202002", "p202003", "pmax"),
+			target:      monthly("p202001", "p202002", "pmax"),
+			expectedSql: "ALTER TABLE engines DROP PARTITION p202001;\nALTER TABLE engines REORGANIZE PARTITION pmax INTO (PARTITION p202003 VALUES LESS THAN (to_days('2020-03-01')), PARTITION pmax VALUES LESS THAN MAXVALUE);",
+		},
+		{
+			source:      monthly("p202001", "p202002"),
+			target:      monthly("p202001"),
+			expectedSql: "ALTER TABLE engines ADD PARTITION (PARTITION p202002 VALUES LESS THAN (to_days('2020-02-01')));",
+		},
+		{
+			source:      nil,
+			target:      monthly("pmax"),
+			expectedSql: "ALTER TABLE engines REMOVE PARTITIONING;",
+		},
+	}
+	source := sourceSchema.(*MysqlSchema).CoreElements["table"]["engines"].(*Table)
+	target := targetSchema.(*MysqlSchema).CoreElements["table"]["engines"].(*Table)
+	for _, c := range cases {
+		source.Partitioning = c.source
+		target.Partitioning = c.target
+		sqls := strings.Join(source.AlterDefinition(target, sqlrog.DEFAULT_SQL_SEP), "\n")
+		if sqls != c.expectedSql {
+			t.Errorf("Expected partitioning sql is not equal to real: \n%s\n%s\n", c.expectedSql, sqls)
+		}
+	}
+}
+
+func TestTableOptionsAlterSQL(t *testing.T) {
+	reloadSchemas()
+	table := sourceSchema.(*MysqlSchema).CoreElements["table"]["engines"].(*Table)
+	table.Engine = "MyISAM"
+	table.Charset = "utf8"
+	table.Collate = "utf8_general_ci"
+	table.Options.ParseCreateOptions("row_format=COMPRESSED KEY_BLOCK_SIZE=8 partitioned")
+	table.Options.Comment = "engine types"
+	changes := myEngine.SchemaDiff(sourceSchema, targetSchema)
+	if len(changes) == 0 || changes[0].State != sqlrog.DIFF_TYPE_UPDATE {
+		t.Fatalf("Expected update table diff is missing for table: %s\n", table.Name)
+	}
+	expectedSqls := []string{
+		"ALTER TABLE engines ENGINE=MyISAM;",
+		"ALTER TABLE engines CONVERT TO CHARACTER SET utf8 COLLATE utf8_general_ci;",
+		"ALTER TABLE engines ROW_FORMAT=COMPRESSED KEY_BLOCK_SIZE=8 COMMENT='engine types';",
+	}
+	sqls := changes[0].DiffSql(sqlrog.DEFAULT_SQL_SEP)
+	if strings.Join(sqls, "\n") != strings.Join(expectedSqls, "\n") {
+		t.Errorf("Expected table options sql is not equal to real: \n%s\n%s\n", strings.Join(expectedSqls, "\n"), strings.Join(sqls, "\n"))
+	}
+}
+
+func TestColumnDefinitionSQL(t *testing.T) {
+	reloadSchemas()
+	table := sourceSchema.(*MysqlSchema).CoreElements["table"]["engines"].(*Table)
+	table.Fields["updated"] = &TableColumn{
+		Name:       "updated",
+		Type:       "timestamp",
+		NotNull:    true,
+		UseDefault: true,
+		Default:    "CURRENT_TIMESTAMP",
+		Extra:      "on update CURRENT_TIMESTAMP",
+		Position:   3,
+	}
+	table.Fields["label"] = &TableColumn{
+		Name:                 "label",
+		Type:                 "varchar(100)",
+		Extra:                "STORED GENERATED",
+		Position:             4,
+		UseDefault:           true,
+		Default:              "ignored",
+		GenerationExpression: "concat(`id`,'-',`name`)",
+	}
+	table.Fields["code"] = &TableColumn{
+		Name:                 "code",
+		Type:                 "varchar(20)",
+		Charset:              "utf8",
+		Collate:              "utf8_bin",
+		Extra:                "VIRTUAL GENERATED",
+		Position:             5,
+		GenerationExpression: "upper(`name`)",
+	}
+	for _, column := range table.Fields {
+		column.ParseExtra()
+	}
+	changes := myEngine.SchemaDiff(sourceSchema, targetSchema)
+	if len(changes) == 0 || changes[0].State != sqlrog.DIFF_TYPE_UPDATE {
+		t.Fatalf("Expected update table diff is missing for table: %s\n", table.Name)
+	}
+	expectedSqls := []string{
+		"ALTER TABLE engines ADD COLUMN updated timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP;",
+		"ALTER TABLE engines ADD COLUMN label varchar(100) GENERATED ALWAYS AS (concat(`id`,'-',`name`)) STORED;",
+		"ALTER TABLE engines ADD COLUMN code varchar(20) CHARACTER SET utf8 COLLATE utf8_bin GENERATED ALWAYS AS (upper(`name`)) VIRTUAL;",
+	}
+	sqls := strings.Join(changes[0].DiffSql(sqlrog.DEFAULT_SQL_SEP), "\n")
+	for _, expected := range expectedSqls {
+		if !strings.Contains(sqls, expected) {
+			t.Errorf("Expected column sql is missing: \n%s\n%s\n", expected, sqls)
+		}
+	}
+	if id := table.Fields["id"]; !id.AutoIncrement || id.Extra != "" {
+		t.Errorf("Expected auto_increment of the project file to be parsed, got extra: %s\n", id.Extra)
+	}
+}
+
+func TestIndexDefinitionSQL(t *testing.T) {
+	indexes := []struct {
+		index       *Index
+		expectedSql string
+	}{
+		{
+			index: &Index{Name: "idx_name", Type: INDEX, Algorithm: "BTREE", TableName: "cars", Comment: "search by name",
+				Fields: map[string]IndexField{"name": {Name: "name", Position: 1, Length: 10}, "id": {Name: "id", Position: 2}}},
+			expectedSql: "CREATE INDEX idx_name ON cars (name(10),id) USING BTREE COMMENT 'search by name';",
+		},
+		{
+			index: &Index{Name: "ft_name", Type: INDEX, Algorithm: FULLTEXT, TableName: "cars",
+				Fields: map[string]IndexField{"name": {Name: "name", Position: 1}}},
+			expectedSql: "CREATE FULLTEXT INDEX ft_name ON cars (name);",
+		},
+		{
+			index: &Index{Name: "uq_serial", Type: UNIQUE, Algorithm: "HASH", TableName: "cars",
+				Fields: map[string]IndexField{"serial": {Name: "serial", Position: 1}}},
+			expectedSql: "ALTER TABLE cars ADD CONSTRAINT uq_serial UNIQUE (serial) USING HASH;",
+		},
+	}
+	for _, c := range indexes {
+		if sql := c.index.Definition(sqlrog.DEFAULT_SQL_SEP); sql != c.expectedSql {
+			t.Errorf("Expected index sql is not equal to real: \n%s\n%s\n", c.expectedSql, sql)
+		}
+	}
+	prefixed := *indexes[0].index
+	prefixed.Fields = map[string]IndexField{"name": {Name: "name", Position: 1, Length: 20}, "id": {Name: "id", Position: 2}}
+	if indexes[0].index.Equals(&prefixed) {
+		t.Error("Expected indexes with different prefix lengths not to be equal")
+	}
+}
+
+func TestRoutineCharacteristicsSQL(t *testing.T) {
+	reloadSchemas()
+	schema := sourceSchema.(*MysqlSchema)
+	view := schema.CoreElements[CORE_ELEMENT_VIEW_NAME]["cars_view"].(*View)
+	view.SqlSecurity = "INVOKER"
+	view.Algorithm = "MERGE"
+	view.CheckOption = "CASCADED"
+	procedure := schema.CoreElements[CORE_ELEMENT_PROCEDURE_NAME]["GetAllCarsByColor"].(*Procedure)
+	procedure.SqlSecurity = "INVOKER"
+	procedure.DataAccess = "READS SQL DATA"
+	procedure.SqlMode = "ANSI_QUOTES"
+	procedure.Comment = "cars by color"
+	schema.RewriteDefiner("app@%")
+
+	// the definer is rewritten for the function and the event as well
+	changes := myEngine.SchemaDiff(sourceSchema, targetSchema)
+	if len(changes) != 4 {
+		t.Fatalf("Expected view, procedure, function and event diffs, got %d\n", len(changes))
+	}
+	expectedView := "CREATE OR REPLACE ALGORITHM=MERGE DEFINER=`app`@`%` SQL SECURITY INVOKER VIEW cars_view \n" +
+		"as select * from cars\nWITH CASCADED CHECK OPTION;"
+	if sql := view.CreateDefinition(sqlrog.DEFAULT_SQL_SEP); sql[0] != expectedView {
+		t.Errorf("Expected view sql is not equal to real: \n%s\n%s\n", expectedView, sql[0])
+	}
+	sql := procedure.CreateDefinition(sqlrog.DEFAULT_SQL_SEP)
+	if len(sql) != 4 || sql[1] != "SET SESSION sql_mode = 'ANSI_QUOTES';" ||
+		!strings.HasPrefix(sql[2], "CREATE DEFINER=`app`@`%` PROCEDURE GetAllCarsByColor(") ||
+		!strings.Contains(sql[2], ") COMMENT 'cars by color' READS SQL DATA SQL SECURITY INVOKER\nBEGIN") {
+		t.Errorf("Unexpected procedure sql: \n%s\n", strings.Join(sql, "\n"))
+	}
+	// backslashes are kept as they are when the routine mode has NO_BACKSLASH_ESCAPES
+	procedure.SqlMode = "ANSI_QUOTES,NO_BACKSLASH_ESCAPES"
+	procedure.Comment = `cars in C:\data, it's`
+	sql = procedure.CreateDefinition(sqlrog.DEFAULT_SQL_SEP)
+	if len(sql) != 4 || !strings.Contains(sql[2], `COMMENT 'cars in C:\data, it''s'`) {
+		t.Errorf("Unexpected procedure comment with NO_BACKSLASH_ESCAPES: \n%s\n", strings.Join(sql, "\n"))
+	}
+	procedure.SqlMode = "ANSI_QUOTES"
+	sql = procedure.CreateDefinition(sqlrog.DEFAULT_SQL_SEP)
+	if len(sql) != 4 || !strings.Contains(sql[2], `COMMENT 'cars in C:\\data, it''s'`) {
+		t.Errorf("Unexpected procedure comment with backslash escapes: \n%s\n", strings.Join(sql, "\n"))
+	}
+}
+
+func TestQuotedIdentifiersSQL(t *testing.T) {
+	table := &Table{
+		Name:   "order",
+		Engine: "InnoDB",
+		Fields: map[string]*TableColumn{
+			"key":      {Name: "key", Type: "int(11)", NotNull: true, Position: 1},
+			"my`value": {Name: "my`value", Type: "int(11)", Position: 2},
+		},
+		Indexes: map[string]map[string]*Index{
+			PRIMARY_KEY: {"PRIMARY": {Name: "PRIMARY", Type: PRIMARY_KEY, TableName: "order",
+				Fields: map[string]IndexField{"key": {Name: "key", Position: 1}}}},
+		},
+	}
+	expectedSql := "CREATE TABLE `order` (\n\t`key` int(11) NOT NULL,\n\t`my``value` int(11),\n\tPRIMARY KEY(`key`)\n) Engine=InnoDB;"
+	if sql := table.CreateDefinition(sqlrog.DEFAULT_SQL_SEP); sql[0] != expectedSql {
+		t.Errorf("Expected table sql is not equal to real: \n%s\n%s\n", expectedSql, sql[0])
+	}
+	expectedSql = "DELETE FROM `order` WHERE `key` = '1';"
+	value := "1"
+	if sql := dataDialect.DeleteRowDefinition(table, map[string]*string{"key": &value}, sqlrog.DEFAULT_SQL_SEP); sql != expectedSql {
+		t.Errorf("Expected delete sql is not equal to real: \n%s\n%s\n", expectedSql, sql)
+	}
+}
+
+func TestStringLiteralsRoundTrip(t *testing.T) {
+	config := sourceConfig
+	config.ProjectName = "test_literals"
+	schema, err := myEngine.LoadSchema(context.Background(), &config, &sqlrog.YamlSchemaReader{}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	table := schema.(*MysqlSchema).CoreElements[CORE_ELEMENT_TABLE_NAME]["notes"].(*Table)
+	expectedValues := []string{
+		"driver's speed",
+		`C:\temp\'new'`,
+		"first line\nsecond line with a \\ and '' quotes",
+		`it's the "speed"; DROP TABLE notes; --`,
+		`notes\remarks 'n' more`,
+	}
+	var literals []string
+	for _, diff := range myEngine.SchemaDiff(schema, &MysqlSchema{}) {
+		for _, sql := range diff.DiffSql(sqlrog.DEFAULT_SQL_SEP) {
+			values, err := parseStringLiterals(sql)
+			if err != nil {
+				t.Fatalf("%s\n%s\n", err, sql)
+			}
+			literals = append(literals, values...)
+		}
+	}
+	for _, expected := range expectedValues {
+		found := false
+		for _, literal := range literals {
+			found = found || literal == expected
+		}
+		if !found {
+			t.Errorf("Expected literal is missing in sql: %q\n%q\n", expected, literals)
+		}
+	}
+	if comment := table.Fields["speed"].Comment; comment != expectedValues[0] {
+		t.Errorf("Expected comment to be loaded as is: %q\n", comment)
+	}
+}
+
+// parseStringLiterals reads single quoted literals of the sql the way MySQL does.
+func parseStringLiterals(sql string) ([]string, error) {
+	var (
+		literals []string
+		literal  strings.Builder
+		quoted   bool
+	)
+	for i := 0; i < len(sql); i++ {
+		char := sql[i]
+		switch {
+		case !quoted:
+			quoted = char == '\''
+		case char == '\\' && i+1 < len(sql):
+			i++
+			literal.WriteByte(sql[i])
+		case char == '\'' && i+1 < len(sql) && sql[i+1] == '\'':
+			i++
+			literal.WriteByte('\'')
+		case char == '\'':
+			literals = append(literals, literal.String())
+			literal.Reset()
+			quoted = false
+		default:
+			literal.WriteByte(char)
+		}
+	}
+	if quoted {
+		return nil, fmt.Errorf("unterminated literal")
+	}
+
+	return literals, nil
+}
+
+func TestScriptRenderer(t *testing.T) {
+	reloadSchemas()
+	schema := sourceSchema.(*MysqlSchema)
+	procedure := schema.CoreElements[CORE_ELEMENT_PROCEDURE_NAME]["GetAllCarsByColor"].(*Procedure)
+	view := schema.CoreElements[CORE_ELEMENT_VIEW_NAME]["cars_view"].(*View)
+
+	renderer := myEngine.ScriptRenderer()
+	script := renderer.Statements(view.CreateDefinition(""))
+	script += renderer.Statements(procedure.CreateDefinition(""))
+	script += renderer.Statements(view.DropDefinition(""))
+	script += renderer.Finish()
+	expectedScript := "CREATE OR REPLACE VIEW cars_view \nas select * from cars;\n" +
+		"DELIMITER //\n" +
+		"CREATE PROCEDURE GetAllCarsByColor(\n\tIN ColorName varchar(50) CHARACTER SET latin1 COLLATE latin1_swedish_ci)\n" +
+		"BEGIN\n    select * from cars where color = @ColorName;\n END//\n" +
+		"DELIMITER ;\n" +
+		"DROP VIEW cars_view;\n"
+	if script != expectedScript {
+		t.Errorf("Expected script is not equal to real: \n%s\n%s\n", expectedScript, script)
+	}
+	if finish := renderer.Finish(); finish != "" {
+		t.Errorf("Expected delimiter to be restored once, got: %s\n", finish)
+	}
+}
+
+func TestScriptParser(t *testing.T) {
+	script := "-- data fix\n" +
+		"UPDATE cars SET color = 'red; \\'dark\\'' WHERE id = 1; # trailing comment\n" +
+		"/* rename */ UPDATE `cars;` SET name = \"a;b\";\n" +
+		"DELIMITER //\n" +
+		"CREATE PROCEDURE fix()\nBEGIN\n  DELETE FROM cars; -- inner;\nEND//\n" +
+		"DELIMITER ;\n" +
+		"/*!40101 SET NAMES utf8 */;\n" +
+		"SELECT 1"
+	statements, err := myEngine.ScriptParser().Split(script)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []sqlrog.ScriptStatement{
+		{Line: 2, SQL: "UPDATE cars SET color = 'red; \\'dark\\'' WHERE id = 1"},
+		{Line: 3, SQL: "UPDATE `cars;` SET name = \"a;b\""},
+		{Line: 5, SQL: "CREATE PROCEDURE fix()\nBEGIN\n  DELETE FROM cars; -- inner;\nEND"},
+		{Line: 10, SQL: "/*!40101 SET NAMES utf8 */"},
+		{Line: 11, SQL: "SELECT 1"},
+	}
+	if len(statements) != len(expected) {
+		t.Fatalf("Expected %d statements, got %d\n", len(expected), len(statements))
+	}
+	for i, statement := range statements {
+		if *statement != expected[i] {
+			t.Errorf("Expected statement %d is not equal to real: \n%v\n%v\n", i, expected[i], *statement)
+		}
+	}
+	if _, err := myEngine.ScriptParser().Split("SELECT 'unterminated;"); err == nil {
+		t.Errorf("Expected unterminated quote error\n")
+	}
+}
+
+func TestHooksAttach(t *testing.T) {
+	reloadSchemas()
+	sourceSchema.(*MysqlSchema).CoreElements[CORE_ELEMENT_TABLE_NAME]["cars"].(*Table).Fields["weight"].NotNull = true
+	diffs := myEngine.SchemaDiff(sourceSchema, targetSchema)
+	sort.SliceStable(diffs, func(i, j int) bool {
+		return diffs[i].Priority > diffs[j].Priority
+	})
+	hooks, err := sqlrog.LoadHooks(sourceConfig.GetAppName(), sourceSchema, myEngine.ScriptParser())
+	if err != nil {
+		t.Fatal(err)
+	}
+	attached := hooks.Attach(diffs)
+	if len(attached) != len(diffs)+2 {
+		t.Fatalf("Expected %d diffs with hooks, got %d\n", len(diffs)+2, len(attached))
+	}
+	if attached[1] != diffs[0] || attached[1].State != sqlrog.DIFF_TYPE_UPDATE || attached[1].Type != CORE_ELEMENT_TABLE_NAME {
+		t.Errorf("Expected alter table diff after the pre hook, got %s\n", attached[1].Type)
+	}
+	if sqls := strings.Join(attached[1].DiffSql(sqlrog.DEFAULT_SQL_SEP), "\n"); !strings.Contains(sqls, "weight int(11) NOT NULL") {
+		t.Errorf("Expected weight column to become NOT NULL after the backfill: \n%s\n", sqls)
+	}
+	expectedSqls := map[int][]string{
+		0:                 {"UPDATE cars SET weight = 0 WHERE weight IS NULL;"},
+		len(attached) - 1: {"CREATE PROCEDURE refresh_summary()\nBEGIN\n  DELETE FROM cars_summary;\nEND;", "CALL refresh_summary();"},
+	}
+	for i, expected := range expectedSqls {
+		hook := attached[i]
+		if hook.Type != sqlrog.CORE_ELEMENT_HOOK_NAME || !hook.SqlOnly {
+			t.Errorf("Expected SQL only hook at %d, got %s\n", i, hook.Type)
+			continue
+		}
+		if sqls := hook.DiffSql(sqlrog.DEFAULT_SQL_SEP); strings.Join(sqls, "\n") != strings.Join(expected, "\n") {
+			t.Errorf("Expected hook sqls are not equal to real: \n%v\n%v\n", expected, sqls)
+		}
+	}
+	if attached := hooks.Attach(nil); len(attached) != 0 {
+		t.Errorf("Expected hooks to be skipped when there is nothing to change\n")
+	}
+
+	// indexes and triggers of a new table are separate diffs, hooks of the table surround them too
+	diffs = myEngine.SchemaDiff(sourceSchema, &MysqlSchema{})
+	sort.SliceStable(diffs, func(i, j int) bool {
+		return diffs[i].Priority > diffs[j].Priority
+	})
+	post := &sqlrog.Hook{Path: "tables/cars.post.sql", Statements: []string{"ANALYZE TABLE cars"}}
+	hooks.ElementPost["tables/cars"] = post
+	positions := make(map[string]int)
+	for i, diff := range hooks.Attach(diffs) {
+		switch element := diff.To.(type) {
+		case *sqlrog.Hook:
+			positions[element.Path] = i
+		case *Table:
+			positions[element.Name] = i
+		case *Index:
+			if element.TableName == "cars" {
+				positions["cars children"] = i
+			}
+		case *Trigger:
+			if element.TableName == "cars" {
+				positions["cars children"] = i
+			}
+		}
+	}
+	for _, key := range []string{"./test_db/tables/cars.pre.sql", "cars", "cars children", "tables/cars.post.sql"} {
+		if _, ok := positions[key]; !ok {
+			t.Fatalf("Expected %s in diffs with hooks\n", key)
+		}
+	}
+	if positions["./test_db/tables/cars.pre.sql"] > positions["cars"] {
+		t.Errorf("Expected pre hook of cars table to run before the table is created\n")
+	}
+	if positions["tables/cars.post.sql"] < positions["cars children"] {
+		t.Errorf("Expected post hook of cars table to run after its indexes and triggers are created\n")
+	}
+}
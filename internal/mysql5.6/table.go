@@ -2,6 +2,7 @@ package mysql
 
 import (
 	"bytes"
+	"context"
 	"database/sql"
 	"fmt"
 	"github.com/stpatrickw/sqlrog/internal/sqlrog"
@@ -24,6 +25,9 @@ type Table struct {
 	Charset                  string
 	Collate                  string
 	Engine                   string
+	Options                  TableOptions    `yaml:",inline"`
+	Partitioning             *Partitioning   `yaml:"partitioning,omitempty"`
+	Data                     sqlrog.DataRows `yaml:"data,omitempty"`
 }
 
 func (t *Table) GetName() string {
@@ -39,8 +43,8 @@ func (t *Table) GetPluralTypeName() string {
 }
 
 func (t *Table) AlterDefinition(t2 interface{}, sep string) []string {
-	var definitions []string
 	other := t.CastType(t2)
+	definitions := t.OptionsAlterDefinition(other, sep)
 	my := &MysqlEngine{}
 	var diffs []*sqlrog.DiffObject
 	if !my.Equals(t.Fields, other.Fields) {
@@ -64,6 +68,7 @@ func (t *Table) AlterDefinition(t2 interface{}, sep string) []string {
 			definitions = append(definitions, diff.DiffSql(sep)...)
 		}
 	}
+	definitions = append(definitions, t.PartitioningAlterDefinition(t.Partitioning, other.Partitioning, sep)...)
 
 	return definitions
 }
@@ -73,15 +78,16 @@ func (t *Table) CreateDefinition(sep string) []string {
 }
 
 func (t *Table) DropDefinition(sep string) []string {
-	return []string{fmt.Sprintf("DROP TABLE %s%s", t.Name, sep)}
+	return []string{fmt.Sprintf("DROP TABLE %s%s", QuoteIdentifier(t.Name), sep)}
 }
 
 func (t *Table) Definition() string {
-	tableTmpl, err := template.New("table").Parse(`TABLE {{ .Name }} (
+	tableTmpl, err := template.New("table").Funcs(templateFuncs).Parse(`TABLE {{ quote .Name }} (
 	{{$first := true}}{{range .Fields }}{{if $first}}{{$first = false}}{{else}},
-	{{end}}{{ .Name }} {{ .Type }}{{if ne .Charset "" }} CHARACTER SET {{ .Charset }}{{end}}{{if ne .Collate "" }} COLLATE {{ .Collate }}{{end}}{{if .NotNull }} NOT NULL{{end}}{{if .UseDefault }} DEFAULT '{{ .Default }}'{{end}}{{if ne .Comment "" }} COMMENT '{{ .Comment }}'{{end}}{{if ne .Extra "" }} {{ .Extra }}{{end}}{{end}}{{if ne .PrimaryKeyFields ""}},
+	{{end}}{{ .Definition }}{{end}}{{if ne .PrimaryKeyFields ""}},
 	PRIMARY KEY({{.PrimaryKeyFields}}){{end}}
-) Engine={{.Engine}}{{ if ne .Charset ""}} CHARSET={{.Charset}}{{end}}`)
+) Engine={{.Engine}}{{ if ne .Charset ""}} CHARSET={{.Charset}}{{end}}{{if ne .Options ""}} {{.Options}}{{end}}{{if .Partitioning}}
+{{.Partitioning.Definition}}{{end}}`)
 
 	if err != nil {
 		return ""
@@ -95,16 +101,20 @@ func (t *Table) Definition() string {
 		Charset          string
 		Collate          string
 		Engine           string
+		Options          string
+		Partitioning     *Partitioning
 	}{
-		Name:    t.Name,
-		Fields:  OrderedColumnFields(t.Fields),
-		Charset: t.Charset,
-		Collate: t.Collate,
-		Engine:  t.Engine,
+		Name:         t.Name,
+		Fields:       OrderedColumnFields(t.Fields),
+		Charset:      t.Charset,
+		Collate:      t.Collate,
+		Engine:       t.Engine,
+		Options:      t.Options.Definition(nil),
+		Partitioning: t.Partitioning,
 	}
 	if primary := t.Indexes[PRIMARY_KEY]; primary != nil {
 		for _, primaryKey := range primary {
-			table.PrimaryKeyFields = OrderedIndexFields(primaryKey.Fields)
+			table.PrimaryKeyFields = IndexFieldsDefinition(primaryKey.Fields)
 		}
 	}
 	err = tableTmpl.Execute(&tpl, table)
@@ -120,51 +130,19 @@ func (t *Table) DiffColumnDefinition(diff *sqlrog.DiffObject, sep string) []stri
 	switch diff.State {
 	case sqlrog.DIFF_TYPE_CREATE:
 		column := diff.To.(*TableColumn)
-		definition := fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s %s", t.Name, column.Name, column.Type)
-		if column.Charset != "" {
-			definition += " CHARACTER SET " + column.Charset
-		}
-		if column.Collate != "" {
-			definition += " COLLATE " + column.Collate
-		}
-		if column.NotNull {
-			definition += " NOT NULL"
-		}
-		if column.Default != "" {
-			definition += " DEFAULT " + column.Default
-		}
-		if column.Comment != "" {
-			definition += " COMMENT '" + column.Comment + "'"
-		}
-		definitions = append(definitions, definition+sep)
+		definitions = append(definitions, fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s%s", QuoteIdentifier(t.Name), column.Definition(), sep))
 	case sqlrog.DIFF_TYPE_DROP:
 		column := diff.From.(*TableColumn)
-		definitions = append(definitions, fmt.Sprintf("ALTER TABLE %s DROP %s%s", t.Name, column.Name, sep))
+		definitions = append(definitions, fmt.Sprintf("ALTER TABLE %s DROP %s%s", QuoteIdentifier(t.Name), QuoteIdentifier(column.Name), sep))
 	case sqlrog.DIFF_TYPE_UPDATE:
 		column := diff.From.(*TableColumn)
-		definition := fmt.Sprintf("ALTER TABLE %s CHANGE COLUMN %s %s %s", t.Name, column.Name, column.Name, column.Type)
-		if column.Charset != "" {
-			definition += " CHARACTER SET " + column.Charset
-		}
-		if column.Collate != "" {
-			definition += " COLLATE " + column.Collate
-		}
-		if column.NotNull {
-			definition += " NOT NULL"
-		}
-		if column.Default != "" {
-			definition += " DEFAULT " + column.Default
-		}
-		if column.Comment != "" {
-			definition += " COMMENT '" + column.Comment + "'"
-		}
-		definitions = append(definitions, definition+sep)
+		definitions = append(definitions, fmt.Sprintf("ALTER TABLE %s CHANGE COLUMN %s %s%s", QuoteIdentifier(t.Name), QuoteIdentifier(column.Name), column.Definition(), sep))
 	}
 	return definitions
 }
 
 func (t *Table) CommentOnColumn(column *TableColumn, sep string) string {
-	return fmt.Sprintf("COMMENT ON COLUMN %s.%s IS '%s'%s\n", t.Name, column.Name, column.Comment, sep)
+	return fmt.Sprintf("COMMENT ON COLUMN %s.%s IS %s%s\n", QuoteIdentifier(t.Name), QuoteIdentifier(column.Name), StringLiteral(column.Comment), sep)
 }
 
 func (t *Table) Equals(t2 interface{}) bool {
@@ -185,6 +163,18 @@ func (t *Table) Equals(t2 interface{}) bool {
 		return false
 	}
 
+	if t.Engine != other.Engine || t.Charset != other.Charset || t.Collate != other.Collate || !t.Options.Equals(&other.Options) {
+		return false
+	}
+
+	if !PartitioningEquals(t.Partitioning, other.Partitioning) {
+		return false
+	}
+
+	if !sqlrog.DataRowsEqual(t.Data, other.Data) {
+		return false
+	}
+
 	return true
 }
 
@@ -215,42 +205,52 @@ func (t *Table) DiffsOnCreate(schema sqlrog.ElementSchema) []*sqlrog.DiffObject
 	})
 
 	fb := &MysqlEngine{}
+	var childDiffs []*sqlrog.DiffObject
 	for typeName, indexesByType := range t.Indexes {
 		if typeName != PRIMARY_KEY {
-			diffs = append(diffs, fb.CompareScheme(indexesByType, nil)...)
+			childDiffs = append(childDiffs, fb.CompareScheme(indexesByType, nil)...)
 		}
 	}
-	diffs = append(diffs, fb.CompareScheme(t.Triggers, nil)...)
+	childDiffs = append(childDiffs, fb.CompareScheme(t.Triggers, nil)...)
+	// indexes and triggers are saved in the table file
+	for _, diff := range childDiffs {
+		diff.SqlOnly = true
+	}
 
-	return diffs
+	return append(diffs, childDiffs...)
 }
 
 func (t *Table) CastType(other interface{}) *Table {
 	return other.(*Table)
 }
 
-func (t *Table) FetchElementsFromDB(conn *sql.DB) ([]sqlrog.ElementSchema, error) {
+func (t *Table) FetchElementsFromDB(ctx context.Context, conn *sql.DB, filter *sqlrog.ElementFilter) ([]sqlrog.ElementSchema, error) {
 	tablesMap := make(map[string]*Table)
-	rows, err := conn.Query(`
-		SELECT t.table_name, t.engine, t.table_collation, c.character_set_name 
+	condition, args := FilterCondition(filter, "t.table_name")
+	rows, err := conn.QueryContext(ctx, `
+		SELECT t.table_name, t.engine, t.table_collation, c.character_set_name, t.table_comment,
+			coalesce(t.auto_increment, 0), coalesce(t.create_options, '')
         FROM INFORMATION_SCHEMA.TABLES t 
         LEFT JOIN INFORMATION_SCHEMA.COLLATION_CHARACTER_SET_APPLICABILITY c ON c.COLLATION_NAME=t.TABLE_COLLATION
-        where table_schema = schema() AND TABLE_TYPE = 'BASE TABLE'
-        order by t.table_name`)
+        where table_schema = schema() AND TABLE_TYPE = 'BASE TABLE'`+condition+`
+        order by t.table_name`, args...)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
 	for rows.Next() {
 		table := &Table{Fields: make(map[string]*TableColumn), Indexes: make(map[string]map[string]*Index), Triggers: make(map[string]*Trigger)}
-		err := rows.Scan(&table.Name, &table.Engine, &table.Collate, &table.Charset)
+		var createOptions string
+		err := rows.Scan(&table.Name, &table.Engine, &table.Collate, &table.Charset, &table.Options.Comment,
+			&table.Options.AutoIncrement, &createOptions)
 		if err != nil {
 			return nil, err
 		}
+		table.Options.ParseCreateOptions(createOptions)
 		tablesMap[table.Name] = table
 	}
 	tableFieldEntity := &TableColumn{}
-	tableFields, err := tableFieldEntity.FetchColumnsFromDB(conn)
+	tableFields, err := tableFieldEntity.FetchColumnsFromDB(ctx, conn, filter)
 	if err != nil {
 		return nil, err
 	}
@@ -261,7 +261,7 @@ func (t *Table) FetchElementsFromDB(conn *sql.DB) ([]sqlrog.ElementSchema, error
 		tablesMap[tableName].Fields = fieldsByTable
 	}
 	triggerEntity := &Trigger{}
-	triggers, err := triggerEntity.FetchTriggersFromDB(conn)
+	triggers, err := triggerEntity.FetchTriggersFromDB(ctx, conn, filter)
 	if err != nil {
 		return nil, err
 	}
@@ -272,7 +272,7 @@ func (t *Table) FetchElementsFromDB(conn *sql.DB) ([]sqlrog.ElementSchema, error
 		tablesMap[tableName].Triggers = triggersByTable
 	}
 	indexEntity := &Index{}
-	indexes, err := indexEntity.FetchIndexesFromDB(conn)
+	indexes, err := indexEntity.FetchIndexesFromDB(ctx, conn, filter)
 	if err != nil {
 		return nil, err
 	}
@@ -282,6 +282,16 @@ func (t *Table) FetchElementsFromDB(conn *sql.DB) ([]sqlrog.ElementSchema, error
 		}
 		tablesMap[tableName].Indexes = indexesByTable
 	}
+	partitionEntity := &Partition{}
+	partitionings, err := partitionEntity.FetchPartitionsFromDB(ctx, conn, filter)
+	if err != nil {
+		return nil, err
+	}
+	for tableName, partitioning := range partitionings {
+		if table, ok := tablesMap[tableName]; ok {
+			table.Partitioning = partitioning
+		}
+	}
 	var tables []sqlrog.ElementSchema
 	for _, table := range tablesMap {
 		tables = append(tables, table)
@@ -1,10 +1,21 @@
 package mysql
 
 import (
+	"context"
 	"database/sql"
+	"fmt"
+	"regexp"
+	"strings"
+
 	"github.com/stpatrickw/sqlrog/internal/sqlrog"
 )
 
+var (
+	defaultExpressionRegexp = regexp.MustCompile(`(?i)^(current_timestamp(\(\d*\))?|now\(\d*\)|localtime(stamp)?(\(\d*\))?|b'[01]*')$`)
+	onUpdateRegexp          = regexp.MustCompile(`(?i)on update (\S+)`)
+	generatedRegexp         = regexp.MustCompile(`(?i)(VIRTUAL|STORED) GENERATED`)
+)
+
 type TableColumn struct {
 	sqlrog.BaseElementSchema `yaml:"base,omitempty"`
 	Name                     string
@@ -15,6 +26,11 @@ type TableColumn struct {
 	UseDefault               bool
 	Default                  string
 	Key                      string
+	DefaultExpression        bool   `yaml:"default_expression,omitempty"`
+	OnUpdate                 string `yaml:"on_update,omitempty"`
+	AutoIncrement            bool   `yaml:"auto_increment,omitempty"`
+	Generated                string `yaml:"generated,omitempty"`
+	GenerationExpression     string `yaml:"generation_expression,omitempty"`
 	Extra                    string
 	Comment                  string
 	Position                 int
@@ -25,7 +41,74 @@ func (t *TableColumn) Equals(t2 interface{}) bool {
 
 	return t.Type == other.Type && t.UseDefault == other.UseDefault && t.Key == other.Key && t.NotNull == other.NotNull &&
 		t.Extra == other.Extra && t.Charset == other.Charset && t.Collate == other.Collate && t.Default == other.Default &&
-		t.Comment == other.Comment && t.Position == other.Position
+		t.Comment == other.Comment && t.Position == other.Position && t.DefaultExpression == other.DefaultExpression &&
+		t.OnUpdate == other.OnUpdate && t.AutoIncrement == other.AutoIncrement && t.Generated == other.Generated &&
+		t.GenerationExpression == other.GenerationExpression
+}
+
+// Definition returns the column definition used by CREATE TABLE, ADD COLUMN and CHANGE COLUMN.
+func (f *TableColumn) Definition() string {
+	definition := QuoteIdentifier(f.Name) + " " + f.Type
+	if f.Charset != "" {
+		definition += " CHARACTER SET " + f.Charset
+	}
+	if f.Collate != "" {
+		definition += " COLLATE " + f.Collate
+	}
+	// charset and collation are a part of the data type, so they go before the generated clause
+	if f.Generated != "" {
+		definition += fmt.Sprintf(" GENERATED ALWAYS AS (%s) %s", f.GenerationExpression, f.Generated)
+	}
+	if f.NotNull {
+		definition += " NOT NULL"
+	}
+	if f.UseDefault && f.Generated == "" {
+		if f.DefaultExpression {
+			definition += " DEFAULT " + f.Default
+		} else {
+			definition += " DEFAULT " + StringLiteral(f.Default)
+		}
+	}
+	if f.OnUpdate != "" {
+		definition += " ON UPDATE " + f.OnUpdate
+	}
+	if f.AutoIncrement {
+		definition += " AUTO_INCREMENT"
+	}
+	if f.Comment != "" {
+		definition += " COMMENT " + StringLiteral(f.Comment)
+	}
+	if f.Extra != "" {
+		definition += " " + f.Extra
+	}
+
+	return definition
+}
+
+// ParseExtra moves known attributes of information_schema EXTRA into their own fields,
+// so only unknown attributes are left in Extra. It's also applied to columns of older project files.
+func (f *TableColumn) ParseExtra() {
+	extra := f.Extra
+	if strings.Contains(strings.ToLower(extra), "auto_increment") {
+		f.AutoIncrement = true
+		extra = strings.NewReplacer("auto_increment", "", "AUTO_INCREMENT", "").Replace(extra)
+	}
+	if match := onUpdateRegexp.FindStringSubmatch(extra); match != nil {
+		f.OnUpdate = match[1]
+		extra = strings.Replace(extra, match[0], "", 1)
+	}
+	if match := generatedRegexp.FindStringSubmatch(extra); match != nil {
+		f.Generated = strings.ToUpper(match[1])
+		extra = strings.Replace(extra, match[0], "", 1)
+	}
+	if strings.Contains(extra, "DEFAULT_GENERATED") {
+		f.DefaultExpression = true
+		extra = strings.Replace(extra, "DEFAULT_GENERATED", "", 1)
+	}
+	if f.UseDefault && defaultExpressionRegexp.MatchString(f.Default) {
+		f.DefaultExpression = true
+	}
+	f.Extra = strings.TrimSpace(extra)
 }
 
 func (f *TableColumn) Diff(t2 interface{}) *sqlrog.DiffObject {
@@ -51,16 +134,32 @@ func (f *TableColumn) GetTypeName() string {
 	return "table_column"
 }
 
-func (f *TableColumn) FetchColumnsFromDB(conn *sql.DB) (map[string]map[string]*TableColumn, error) {
+func (f *TableColumn) FetchColumnsFromDB(ctx context.Context, conn *sql.DB, filter *sqlrog.ElementFilter) (map[string]map[string]*TableColumn, error) {
 	fields := make(map[string]map[string]*TableColumn)
 
-	fieldRows, err := conn.Query(`
+	condition, args := FilterCondition(filter, "t.table_name")
+
+	// Generated columns appeared in MySQL 5.7, older servers have no GENERATION_EXPRESSION column
+	generationExpression := "''"
+	var hasGeneration int
+	err := conn.QueryRowContext(ctx, `
+		SELECT COUNT(*) FROM INFORMATION_SCHEMA.COLUMNS
+		WHERE table_schema = 'information_schema' AND table_name = 'COLUMNS' AND column_name = 'GENERATION_EXPRESSION'`).Scan(&hasGeneration)
+	if err != nil {
+		return nil, err
+	}
+	if hasGeneration > 0 {
+		generationExpression = "coalesce(c.generation_expression, '')"
+	}
+
+	fieldRows, err := conn.QueryContext(ctx, `
 			SELECT t.table_name, c.column_name, c.column_type, c.is_nullable, 
 				case when c.column_default is null then 0 else 1 end, coalesce(c.column_default, ''),
-           		c.column_key, c.extra, coalesce(c.character_set_name, ''), coalesce(c.collation_name, ''), c.column_comment, c.ordinal_position
+           		c.column_key, c.extra, coalesce(c.character_set_name, ''), coalesce(c.collation_name, ''), c.column_comment, c.ordinal_position,
+				`+generationExpression+`
 			FROM INFORMATION_SCHEMA.TABLES t 
 			JOIN INFORMATION_SCHEMA.COLUMNS c ON t.table_schema = c.table_schema and t.table_name = c.table_name
-			WHERE t.table_schema = schema() AND t.TABLE_TYPE = 'BASE TABLE' ORDER BY c.ordinal_position`)
+			WHERE t.table_schema = schema() AND t.TABLE_TYPE = 'BASE TABLE'`+condition+` ORDER BY c.ordinal_position`, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -73,7 +172,7 @@ func (f *TableColumn) FetchColumnsFromDB(conn *sql.DB) (map[string]map[string]*T
 			nullable     string
 			useDefault   int
 		)
-		err := fieldRows.Scan(&relationName, &field.Name, &field.Type, &nullable, &useDefault, &field.Default, &field.Key, &field.Extra, &field.Charset, &field.Collate, &field.Comment, &field.Position)
+		err := fieldRows.Scan(&relationName, &field.Name, &field.Type, &nullable, &useDefault, &field.Default, &field.Key, &field.Extra, &field.Charset, &field.Collate, &field.Comment, &field.Position, &field.GenerationExpression)
 		if err != nil {
 			return nil, err
 		}
@@ -83,6 +182,7 @@ func (f *TableColumn) FetchColumnsFromDB(conn *sql.DB) (map[string]map[string]*T
 		if useDefault == 1 {
 			field.UseDefault = true
 		}
+		field.ParseExtra()
 		if _, ok := fields[relationName]; !ok {
 			fields[relationName] = make(map[string]*TableColumn)
 		}
@@ -91,3 +191,11 @@ func (f *TableColumn) FetchColumnsFromDB(conn *sql.DB) (map[string]map[string]*T
 
 	return fields, nil
 }
+
+func (mys *MysqlSchema) ParseColumnsExtra() {
+	for _, element := range mys.CoreElements[CORE_ELEMENT_TABLE_NAME] {
+		for _, column := range element.(*Table).Fields {
+			column.ParseExtra()
+		}
+	}
+}
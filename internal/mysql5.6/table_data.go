@@ -0,0 +1,76 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+
+	"github.com/stpatrickw/sqlrog/internal/sqlrog"
+)
+
+const TABLE_DATA_PRIORITY = 6
+
+var dataDialect = &sqlrog.DataDialect{Quoter: identifierQuoter, StringLiteral: StringLiteral}
+
+func (my *MysqlEngine) DataDiff(source *MysqlSchema, target *MysqlSchema) *sqlrog.DiffObject {
+	return sqlrog.DataDiff(source.TableData(), target.TableData(), TABLE_DATA_PRIORITY)
+}
+
+func (mys *MysqlSchema) TableData() *sqlrog.TableData {
+	return sqlrog.NewTableData(mys.DataTables(), dataDialect)
+}
+
+func (mys *MysqlSchema) TrackData(config *sqlrog.Config) {
+	sqlrog.TrackData(mys.DataTables(), config)
+}
+
+func (mys *MysqlSchema) FetchDataFromDB(ctx context.Context, conn *sql.DB, config *sqlrog.Config) error {
+	return sqlrog.FetchData(ctx, conn, mys.DataTables(), config, dataDialect)
+}
+
+func (mys *MysqlSchema) DataTables() []sqlrog.DataTable {
+	var tables []sqlrog.DataTable
+	for _, element := range mys.CoreElements[CORE_ELEMENT_TABLE_NAME] {
+		tables = append(tables, element.(*Table))
+	}
+
+	return tables
+}
+
+func (t *Table) GetData() sqlrog.DataRows {
+	return t.Data
+}
+
+func (t *Table) SetData(data sqlrog.DataRows) {
+	t.Data = data
+}
+
+// ReferencedTables returns tables referenced by foreign keys, their rows are inserted first.
+func (t *Table) ReferencedTables() []string {
+	var tables []string
+	for _, foreignKey := range t.Indexes[FOREIGN_KEY] {
+		tables = append(tables, foreignKey.SourceTable)
+	}
+
+	return tables
+}
+
+// DataColumns leaves out generated columns, they are derived from the rest ones and can't be written.
+func (t *Table) DataColumns() []string {
+	var columns []string
+	for _, column := range OrderedColumnFields(t.Fields) {
+		if column.Generated == "" {
+			columns = append(columns, column.Name)
+		}
+	}
+
+	return columns
+}
+
+func (t *Table) PrimaryKeyColumns() []string {
+	for _, primaryKey := range t.Indexes[PRIMARY_KEY] {
+		return strings.Split(OrderedIndexFields(primaryKey.Fields), ",")
+	}
+
+	return nil
+}
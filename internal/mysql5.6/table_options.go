@@ -0,0 +1,99 @@
+package mysql
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// TableOptions keeps optional CREATE TABLE options, empty values stand for server defaults.
+type TableOptions struct {
+	Comment          string `yaml:"comment,omitempty"`
+	RowFormat        string `yaml:"row_format,omitempty"`
+	KeyBlockSize     string `yaml:"key_block_size,omitempty"`
+	StatsPersistent  string `yaml:"stats_persistent,omitempty"`
+	StatsAutoRecalc  string `yaml:"stats_auto_recalc,omitempty"`
+	StatsSamplePages string `yaml:"stats_sample_pages,omitempty"`
+	AutoIncrement    int64  `yaml:"auto_increment,omitempty"`
+}
+
+// ParseCreateOptions fills options which are set explicitly in CREATE_OPTIONS of information_schema.TABLES.
+func (o *TableOptions) ParseCreateOptions(createOptions string) {
+	for _, option := range strings.Fields(createOptions) {
+		pair := strings.SplitN(option, "=", 2)
+		if len(pair) != 2 {
+			continue
+		}
+		switch strings.ToLower(pair[0]) {
+		case "row_format":
+			o.RowFormat = strings.ToUpper(pair[1])
+		case "key_block_size":
+			o.KeyBlockSize = pair[1]
+		case "stats_persistent":
+			o.StatsPersistent = pair[1]
+		case "stats_auto_recalc":
+			o.StatsAutoRecalc = pair[1]
+		case "stats_sample_pages":
+			o.StatsSamplePages = pair[1]
+		}
+	}
+}
+
+func (o *TableOptions) Equals(other *TableOptions) bool {
+	return *o == *other
+}
+
+// Definition returns options which differ from the other ones, or all set options when other is nil.
+func (o *TableOptions) Definition(other *TableOptions) string {
+	if other == nil {
+		other = &TableOptions{}
+	}
+	var options []string
+	option := func(name string, value string, otherValue string, reset string) {
+		if value == otherValue {
+			return
+		}
+		if value == "" {
+			value = reset
+		}
+		options = append(options, name+"="+value)
+	}
+	option("ROW_FORMAT", o.RowFormat, other.RowFormat, "DEFAULT")
+	option("KEY_BLOCK_SIZE", o.KeyBlockSize, other.KeyBlockSize, "0")
+	option("STATS_PERSISTENT", o.StatsPersistent, other.StatsPersistent, "DEFAULT")
+	option("STATS_AUTO_RECALC", o.StatsAutoRecalc, other.StatsAutoRecalc, "DEFAULT")
+	option("STATS_SAMPLE_PAGES", o.StatsSamplePages, other.StatsSamplePages, "DEFAULT")
+	if o.AutoIncrement > 0 && o.AutoIncrement != other.AutoIncrement {
+		options = append(options, "AUTO_INCREMENT="+strconv.FormatInt(o.AutoIncrement, 10))
+	}
+	if o.Comment != other.Comment {
+		options = append(options, "COMMENT="+StringLiteral(o.Comment))
+	}
+
+	return strings.Join(options, " ")
+}
+
+func (t *Table) OptionsAlterDefinition(other *Table, sep string) []string {
+	var definitions []string
+	if t.Engine != other.Engine && t.Engine != "" {
+		definitions = append(definitions, fmt.Sprintf("ALTER TABLE %s ENGINE=%s%s", QuoteIdentifier(t.Name), t.Engine, sep))
+	}
+	if (t.Charset != other.Charset || t.Collate != other.Collate) && t.Charset != "" {
+		definition := fmt.Sprintf("ALTER TABLE %s CONVERT TO CHARACTER SET %s", QuoteIdentifier(t.Name), t.Charset)
+		if t.Collate != "" {
+			definition += " COLLATE " + t.Collate
+		}
+		definitions = append(definitions, definition+sep)
+	}
+	if options := t.Options.Definition(&other.Options); options != "" {
+		definitions = append(definitions, fmt.Sprintf("ALTER TABLE %s %s%s", QuoteIdentifier(t.Name), options, sep))
+	}
+
+	return definitions
+}
+
+func (mys *MysqlSchema) ResetAutoIncrement() {
+	for _, element := range mys.CoreElements[CORE_ELEMENT_TABLE_NAME] {
+		element.(*Table).Options.AutoIncrement = 0
+	}
+}
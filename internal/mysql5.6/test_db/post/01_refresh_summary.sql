@@ -0,0 +1,7 @@
+DELIMITER //
+CREATE PROCEDURE refresh_summary()
+BEGIN
+  DELETE FROM cars_summary;
+END//
+DELIMITER ;
+CALL refresh_summary();
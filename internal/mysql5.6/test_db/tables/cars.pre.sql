@@ -0,0 +1,2 @@
+-- backfill weights before the column becomes NOT NULL
+UPDATE cars SET weight = 0 WHERE weight IS NULL;
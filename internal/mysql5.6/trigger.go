@@ -2,6 +2,7 @@ package mysql
 
 import (
 	"bytes"
+	"context"
 	"database/sql"
 	"fmt"
 	"github.com/stpatrickw/sqlrog/internal/sqlrog"
@@ -20,8 +21,13 @@ func (t *Trigger) GetTypeName() string {
 	return "trigger"
 }
 
+// HookParentKey lets hooks of the table surround changes of its triggers.
+func (t *Trigger) HookParentKey() string {
+	return CORE_ELEMENT_TABLE_PLURAL_NAME + "/" + t.TableName
+}
+
 func (t *Trigger) AlterDefinition(other interface{}, sep string) []string {
-	return append(t.CastType(other).DropDefinition(sep), t.CastType(other).CreateDefinition(sep)...)
+	return append(t.DropDefinition(sep), t.CreateDefinition(sep)...)
 }
 
 func (t *Trigger) CreateDefinition(sep string) []string {
@@ -29,12 +35,12 @@ func (t *Trigger) CreateDefinition(sep string) []string {
 }
 
 func (t *Trigger) DropDefinition(sep string) []string {
-	return []string{fmt.Sprintf("DROP TRIGGER IF EXISTS %s%s", t.Name, sep)}
+	return []string{fmt.Sprintf("DROP TRIGGER IF EXISTS %s%s", QuoteIdentifier(t.Name), sep)}
 }
 
 func (t *Trigger) Definition() string {
-	procTmpl, err := template.New("procedure").Parse(
-		`TRIGGER {{ .Name }} {{ .TypeName}} ON {{ .TableName }} FOR EACH ROW
+	procTmpl, err := template.New("procedure").Funcs(templateFuncs).Parse(
+		`TRIGGER {{ quote .Name }} {{ .TypeName}} ON {{ quote .TableName }} FOR EACH ROW
 {{ .Source }}`)
 
 	if err != nil {
@@ -76,13 +82,15 @@ func (t *Trigger) CastType(other interface{}) *Trigger {
 	return other.(*Trigger)
 }
 
-func (t *Trigger) FetchTriggersFromDB(conn *sql.DB) (map[string]map[string]*Trigger, error) {
+func (t *Trigger) FetchTriggersFromDB(ctx context.Context, conn *sql.DB, filter *sqlrog.ElementFilter) (map[string]map[string]*Trigger, error) {
 	triggers := make(map[string]map[string]*Trigger)
 
-	rows, err := conn.Query(`
+	condition, args := FilterCondition(filter, "EVENT_OBJECT_TABLE")
+
+	rows, err := conn.QueryContext(ctx, `
 		select EVENT_OBJECT_TABLE, TRIGGER_NAME, CONCAT(ACTION_TIMING, ' ', EVENT_MANIPULATION), ACTION_STATEMENT
 		from information_schema.triggers
-		where TRIGGER_SCHEMA = schema() `)
+		where TRIGGER_SCHEMA = schema()`+condition, args...)
 	if err != nil {
 		return nil, err
 	}
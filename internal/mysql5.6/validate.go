@@ -0,0 +1,32 @@
+package mysql
+
+import (
+	"context"
+
+	"github.com/stpatrickw/sqlrog/internal/sqlrog"
+)
+
+// ValidateSchema reports broken files together with the table errors of the files which are read.
+func (my *MysqlEngine) ValidateSchema(ctx context.Context, config *sqlrog.Config, reader sqlrog.ObjectReader) ([]*sqlrog.ValidationError, error) {
+	schema := &MysqlSchema{
+		sqlrog.BaseElementSchema{
+			CoreElements: make(map[string]map[string]sqlrog.ElementSchema),
+		},
+	}
+	errs := my.ValidateFiles(config.GetAppName(), schema, reader)
+	tables := schema.CoreElements[CORE_ELEMENT_TABLE_NAME]
+	for _, element := range tables {
+		table := element.(*Table)
+		path := my.ElementFilePath(config.GetAppName(), table)
+		for _, message := range table.Validate(tables) {
+			errs = append(errs, &sqlrog.ValidationError{Path: path, Message: message})
+		}
+	}
+	sqlrog.SortValidationErrors(errs)
+
+	return errs, nil
+}
+
+func (t *Table) Validate(tables map[string]sqlrog.ElementSchema) []string {
+	return sqlrog.ValidateTable(t, tables, FOREIGN_KEY)
+}
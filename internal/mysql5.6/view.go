@@ -2,9 +2,11 @@ package mysql
 
 import (
 	"bytes"
+	"context"
 	"database/sql"
 	"fmt"
 	"github.com/stpatrickw/sqlrog/internal/sqlrog"
+	"regexp"
 	"text/template"
 )
 
@@ -13,10 +15,16 @@ const (
 	CORE_ELEMENT_VIEW_PLURAL_NAME = "views"
 )
 
+var viewAlgorithmRegexp = regexp.MustCompile(`ALGORITHM=(\w+)`)
+
 type View struct {
 	sqlrog.BaseElementSchema `yaml:"base,omitempty"`
 	Name                     string `yaml:"name"`
 	Source                   string `yaml:"source"`
+	Definer                  string `yaml:"definer,omitempty"`
+	SqlSecurity              string `yaml:"sql_security,omitempty"`
+	Algorithm                string `yaml:"algorithm,omitempty"`
+	CheckOption              string `yaml:"check_option,omitempty"`
 }
 
 func (v *View) GetName() string {
@@ -40,12 +48,14 @@ func (v *View) CreateDefinition(sep string) []string {
 }
 
 func (v *View) DropDefinition(sep string) []string {
-	return []string{fmt.Sprintf("DROP VIEW %s%s", v.Name, sep)}
+	return []string{fmt.Sprintf("DROP VIEW %s%s", QuoteIdentifier(v.Name), sep)}
 }
 
 func (v *View) Definition(sep string) string {
-	procTmpl, err := template.New("view").Parse(`CREATE OR REPLACE VIEW {{ .Name}} 
-as {{ .Source }}`)
+	procTmpl, err := template.New("view").Funcs(templateFuncs).Parse(`CREATE OR REPLACE {{if .Algorithm}}ALGORITHM={{ .Algorithm}} {{end}}` +
+		`{{if .Definer}}DEFINER={{ .DefinerDefinition}} {{end}}{{if .SqlSecurity}}SQL SECURITY {{ .SqlSecurity}} {{end}}VIEW {{ quote .Name}} 
+as {{ .Source }}{{if and .CheckOption (ne .CheckOption "NONE")}}
+WITH {{ .CheckOption}} CHECK OPTION{{end}}`)
 
 	if err != nil {
 		return ""
@@ -60,10 +70,15 @@ as {{ .Source }}`)
 	return tpl.String() + sep
 }
 
+func (v *View) DefinerDefinition() string {
+	return DefinerDefinition(v.Definer)
+}
+
 func (p *View) Equals(e2 interface{}) bool {
 	other := p.CastType(e2)
 
-	if p.Name != other.Name || p.Source != other.Source {
+	if p.Name != other.Name || p.Source != other.Source || p.Definer != other.Definer || p.SqlSecurity != other.SqlSecurity ||
+		p.Algorithm != other.Algorithm || p.CheckOption != other.CheckOption {
 		return false
 	}
 
@@ -89,26 +104,41 @@ func (v *View) CastType(other interface{}) *View {
 	return other.(*View)
 }
 
-func (v *View) FetchElementsFromDB(conn *sql.DB) ([]sqlrog.ElementSchema, error) {
+func (v *View) FetchElementsFromDB(ctx context.Context, conn *sql.DB, filter *sqlrog.ElementFilter) ([]sqlrog.ElementSchema, error) {
 	var views []sqlrog.ElementSchema
 
-	rows, err := conn.Query(`
-		SELECT TABLE_NAME, VIEW_DEFINITION 
+	condition, args := FilterCondition(filter, "TABLE_NAME")
+
+	rows, err := conn.QueryContext(ctx, `
+		SELECT TABLE_NAME, VIEW_DEFINITION, DEFINER, SECURITY_TYPE, CHECK_OPTION
 		from INFORMATION_SCHEMA.VIEWS 
-		where TABLE_SCHEMA = schema() 
-		order by 1`)
+		where TABLE_SCHEMA = schema()`+condition+`
+		order by 1`, args...)
 	if err != nil {
 		return views, err
 	}
 	defer rows.Close()
 	for rows.Next() {
 		view := &View{}
-		err := rows.Scan(&view.Name, &view.Source)
+		err := rows.Scan(&view.Name, &view.Source, &view.Definer, &view.SqlSecurity, &view.CheckOption)
 		if err != nil {
 			return nil, err
 		}
 		views = append(views, view)
 	}
+	rows.Close()
+	// information_schema.VIEWS has no algorithm, it's taken from SHOW CREATE VIEW
+	for _, element := range views {
+		view := element.(*View)
+		var name, createView, charset, collation string
+		err := conn.QueryRowContext(ctx, "SHOW CREATE VIEW "+QuoteIdentifier(view.Name)).Scan(&name, &createView, &charset, &collation)
+		if err != nil {
+			return nil, err
+		}
+		if match := viewAlgorithmRegexp.FindStringSubmatch(createView); match != nil {
+			view.Algorithm = match[1]
+		}
+	}
 
 	return views, nil
 }
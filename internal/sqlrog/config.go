@@ -14,6 +14,13 @@ import (
 const DefaultConfigFileName = "config.yml"
 const ProjectTypeFile = "file"
 
+// Policies of generator values sync: raise only, raise to the max of the owning column, or set exactly.
+const (
+	GENERATOR_VALUES_RAISE = "raise"
+	GENERATOR_VALUES_MAX   = "max"
+	GENERATOR_VALUES_EXACT = "exact"
+)
+
 var Engines map[string]Engine
 var ProjectConfig *ProjectsConfig
 
@@ -60,10 +67,16 @@ type Configurable interface {
 }
 
 type Config struct {
-	ProjectName string      `yaml:"project_name" validate:"required"`
-	Engine      string      `yaml:"engine" validate:"required"`
-	AppType     string      `yaml:"type" validate:"required"`
-	Params      interface{} `yaml:"params" validate:"required"`
+	ProjectName     string      `yaml:"project_name" validate:"required"`
+	Engine          string      `yaml:"engine" validate:"required"`
+	AppType         string      `yaml:"type" validate:"required"`
+	Params          interface{} `yaml:"params" validate:"required"`
+	Data            []string    `yaml:"data,omitempty"`
+	Grants          bool        `yaml:"grants,omitempty"`
+	AutoIncrement   bool        `yaml:"auto_increment,omitempty"`
+	Definer         string      `yaml:"definer,omitempty"`
+	Statistics      bool        `yaml:"statistics,omitempty"`
+	GeneratorValues string      `yaml:"generator_values,omitempty"`
 }
 
 func (conf *Config) GetEngineName() string {
@@ -73,6 +86,53 @@ func (conf *Config) GetAppName() string {
 	return conf.ProjectName
 }
 
+func (conf *Config) IsDataTracked(tableName string) bool {
+	for _, table := range conf.Data {
+		if table == tableName {
+			return true
+		}
+	}
+	return false
+}
+
+func (conf *Config) WithData(tables []string) *Config {
+	config := *conf
+	config.Data = tables
+	return &config
+}
+
+func (conf *Config) WithGrants(grants bool) *Config {
+	config := *conf
+	config.Grants = grants
+	return &config
+}
+
+func (conf *Config) WithAutoIncrement(autoIncrement bool) *Config {
+	config := *conf
+	config.AutoIncrement = autoIncrement
+	return &config
+}
+
+func (conf *Config) WithGeneratorValues(policy string) *Config {
+	config := *conf
+	config.GeneratorValues = policy
+	return &config
+}
+
+func IsGeneratorValuesPolicy(policy string) bool {
+	switch policy {
+	case "", GENERATOR_VALUES_RAISE, GENERATOR_VALUES_MAX, GENERATOR_VALUES_EXACT:
+		return true
+	}
+	return false
+}
+
+func (conf *Config) WithDefiner(definer string) *Config {
+	config := *conf
+	config.Definer = definer
+	return &config
+}
+
 func (sc *ProjectsConfig) Load(fileName string) error {
 	if _, err := os.Stat(fileName); os.IsNotExist(err) {
 		if _, err = os.Create(fileName); err != nil {
@@ -0,0 +1,410 @@
+package sqlrog
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+const CORE_ELEMENT_TABLE_DATA_NAME = "table_data"
+
+// DataTable is a table with tracked rows, engines provide its columns and foreign key references.
+type DataTable interface {
+	GetName() string
+	GetData() DataRows
+	SetData(data DataRows)
+	ReferencedTables() []string
+	// DataColumns returns columns ordered by position, derived columns (generated, computed) are left out
+	// as they can't be written.
+	DataColumns() []string
+	PrimaryKeyColumns() []string
+}
+
+// DataDialect writes table data statements the engine way.
+type DataDialect struct {
+	Quoter        *IdentifierQuoter
+	StringLiteral func(value string) string
+}
+
+// TableData is the reference data of all tracked tables of a schema, it's compared as one element so
+// rows are deleted, inserted and updated in the foreign key order.
+type TableData struct {
+	BaseElementSchema `yaml:"base,omitempty"`
+	Tables            map[string]DataTable
+	Dialect           *DataDialect
+}
+
+// NewTableData keeps tables which data is tracked.
+func NewTableData(tables []DataTable, dialect *DataDialect) *TableData {
+	data := &TableData{Tables: make(map[string]DataTable), Dialect: dialect}
+	for _, table := range tables {
+		if table.GetData() != nil {
+			data.Tables[table.GetName()] = table
+		}
+	}
+
+	return data
+}
+
+func (td *TableData) GetName() string {
+	return "data"
+}
+
+func (td *TableData) GetTypeName() string {
+	return CORE_ELEMENT_TABLE_DATA_NAME
+}
+
+func (td *TableData) CreateDefinition(sep string) []string {
+	return td.AlterDefinition(&TableData{Dialect: td.Dialect}, sep)
+}
+
+func (td *TableData) AlterDefinition(other interface{}, sep string) []string {
+	var definitions []string
+	target := td.CastType(other)
+	order := td.DependencyOrder()
+	for i := len(order) - 1; i >= 0; i-- {
+		table := td.Tables[order[i]]
+		if targetTable, ok := target.Tables[table.GetName()]; ok {
+			targetData := targetTable.GetData()
+			for _, key := range targetData.Keys() {
+				if _, ok := table.GetData()[key]; !ok {
+					definitions = append(definitions, td.Dialect.DeleteRowDefinition(table, targetData[key], sep))
+				}
+			}
+		}
+	}
+	for _, name := range order {
+		table := td.Tables[name]
+		var targetData DataRows
+		if targetTable, ok := target.Tables[table.GetName()]; ok {
+			targetData = targetTable.GetData()
+		}
+		data := table.GetData()
+		for _, key := range data.Keys() {
+			row := data[key]
+			if targetRow, ok := targetData[key]; !ok {
+				definitions = append(definitions, td.Dialect.InsertRowDefinition(table, row, sep))
+			} else if !DataRowEquals(row, targetRow) {
+				// rows differing only in derived columns have nothing to update
+				if update := td.Dialect.UpdateRowDefinition(table, row, targetRow, sep); update != "" {
+					definitions = append(definitions, update)
+				}
+			}
+		}
+	}
+
+	return definitions
+}
+
+// Equals compares only tables of this side, data of dropped tables goes away with them.
+func (td *TableData) Equals(other interface{}) bool {
+	target := td.CastType(other)
+	for name, table := range td.Tables {
+		targetTable, ok := target.Tables[name]
+		if !ok || !DataRowsEqual(table.GetData(), targetTable.GetData()) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// DependencyOrder returns table names with referenced tables going first.
+func (td *TableData) DependencyOrder() []string {
+	dependencies := make(map[string][]string)
+	for name, table := range td.Tables {
+		dependencies[name] = []string{}
+		for _, referenced := range table.ReferencedTables() {
+			if referenced != name {
+				dependencies[name] = append(dependencies[name], referenced)
+			}
+		}
+	}
+
+	return DependencyOrder(dependencies)
+}
+
+func (td *TableData) CastType(other interface{}) *TableData {
+	return other.(*TableData)
+}
+
+func DataDiff(source *TableData, target *TableData, priority int) *DiffObject {
+	if len(source.Tables) == 0 || source.Equals(target) {
+		return nil
+	}
+
+	return &DiffObject{
+		State:    DIFF_TYPE_UPDATE,
+		Type:     source.GetTypeName(),
+		From:     source,
+		To:       target,
+		Priority: priority,
+		SqlOnly:  true,
+	}
+}
+
+// TrackData drops data of tables which are not tracked by the project and starts empty data of tracked
+// tables without rows in the project files.
+func TrackData(tables []DataTable, config *Config) {
+	for _, table := range tables {
+		if !config.IsDataTracked(table.GetName()) {
+			table.SetData(nil)
+		} else if table.GetData() == nil {
+			table.SetData(make(DataRows))
+		}
+	}
+}
+
+// FetchData reads rows of tracked tables ordered by primary key.
+func FetchData(ctx context.Context, conn *sql.DB, tables []DataTable, config *Config, dialect *DataDialect) error {
+	for _, table := range tables {
+		if !config.IsDataTracked(table.GetName()) {
+			continue
+		}
+		keyColumns := table.PrimaryKeyColumns()
+		if len(keyColumns) == 0 {
+			return errors.New(fmt.Sprintf("Table %s has no primary key, its data can't be tracked", table.GetName()))
+		}
+		query := fmt.Sprintf("SELECT %s FROM %s ORDER BY %s", strings.Join(dialect.Quoter.QuoteIdentifiers(table.DataColumns()), ","),
+			dialect.Quoter.QuoteIdentifier(table.GetName()), strings.Join(dialect.Quoter.QuoteIdentifiers(keyColumns), ","))
+		rows, err := conn.QueryContext(ctx, query)
+		if err != nil {
+			return err
+		}
+		data, err := ScanDataRows(rows, keyColumns)
+		rows.Close()
+		if err != nil {
+			return err
+		}
+		table.SetData(data)
+	}
+
+	return nil
+}
+
+func (dd *DataDialect) InsertRowDefinition(table DataTable, row map[string]*string, sep string) string {
+	var (
+		columns []string
+		values  []string
+	)
+	for _, column := range table.DataColumns() {
+		if value, ok := row[column]; ok {
+			columns = append(columns, dd.Quoter.QuoteIdentifier(column))
+			values = append(values, dd.Literal(value))
+		}
+	}
+
+	return fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s)%s", dd.Quoter.QuoteIdentifier(table.GetName()), strings.Join(columns, ","), strings.Join(values, ","), sep)
+}
+
+// UpdateRowDefinition sets only changed values, it's empty when the row differs in derived columns only.
+func (dd *DataDialect) UpdateRowDefinition(table DataTable, row map[string]*string, targetRow map[string]*string, sep string) string {
+	var values []string
+	for _, column := range table.DataColumns() {
+		value, ok := row[column]
+		if !ok {
+			continue
+		}
+		if targetValue, ok := targetRow[column]; ok && DataValueEquals(value, targetValue) {
+			continue
+		}
+		values = append(values, fmt.Sprintf("%s = %s", dd.Quoter.QuoteIdentifier(column), dd.Literal(value)))
+	}
+	if len(values) == 0 {
+		return ""
+	}
+
+	return fmt.Sprintf("UPDATE %s SET %s WHERE %s%s", dd.Quoter.QuoteIdentifier(table.GetName()), strings.Join(values, ", "), dd.RowCondition(table, row), sep)
+}
+
+func (dd *DataDialect) DeleteRowDefinition(table DataTable, row map[string]*string, sep string) string {
+	return fmt.Sprintf("DELETE FROM %s WHERE %s%s", dd.Quoter.QuoteIdentifier(table.GetName()), dd.RowCondition(table, row), sep)
+}
+
+func (dd *DataDialect) RowCondition(table DataTable, row map[string]*string) string {
+	var conditions []string
+	for _, column := range table.PrimaryKeyColumns() {
+		conditions = append(conditions, fmt.Sprintf("%s = %s", dd.Quoter.QuoteIdentifier(column), dd.Literal(row[column])))
+	}
+
+	return strings.Join(conditions, " AND ")
+}
+
+func (dd *DataDialect) Literal(value *string) string {
+	if value == nil {
+		return "NULL"
+	}
+
+	return dd.StringLiteral(*value)
+}
+
+// DataRows keeps tracked rows of a table keyed by primary key values, NULL values are nil.
+type DataRows map[string]map[string]*string
+
+func (dr DataRows) Keys() []string {
+	var keys []string
+	for key := range dr {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
+
+var dataRowKeyEscaper = strings.NewReplacer(`\`, `\\`, `,`, `\,`)
+
+// DataRowKey joins primary key values by commas, commas and backslashes of the values are escaped
+// so different composite keys never collide.
+func DataRowKey(values []string) string {
+	escaped := make([]string, len(values))
+	for i, value := range values {
+		escaped[i] = dataRowKeyEscaper.Replace(value)
+	}
+
+	return strings.Join(escaped, ",")
+}
+
+func DataRowsEqual(src DataRows, dest DataRows) bool {
+	if len(src) != len(dest) {
+		return false
+	}
+	for key, row := range src {
+		if _, ok := dest[key]; !ok {
+			return false
+		}
+		if !DataRowEquals(row, dest[key]) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func DataRowEquals(src map[string]*string, dest map[string]*string) bool {
+	if len(src) != len(dest) {
+		return false
+	}
+	for column, value := range src {
+		other, ok := dest[column]
+		if !ok || !DataValueEquals(value, other) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func DataValueEquals(src *string, dest *string) bool {
+	if src == nil || dest == nil {
+		return src == nil && dest == nil
+	}
+	return *src == *dest
+}
+
+func ScanDataRows(rows *sql.Rows, keyColumns []string) (DataRows, error) {
+	columns, err := rows.Columns()
+	if err != nil {
+		return nil, err
+	}
+	for i, column := range columns {
+		columns[i] = strings.TrimSpace(column)
+	}
+	data := make(DataRows)
+	for rows.Next() {
+		values := make([]interface{}, len(columns))
+		pointers := make([]interface{}, len(columns))
+		for i := range values {
+			pointers[i] = &values[i]
+		}
+		if err := rows.Scan(pointers...); err != nil {
+			return nil, err
+		}
+		row := make(map[string]*string)
+		for i, column := range columns {
+			row[column] = dataValueToString(values[i])
+		}
+		var keyValues []string
+		for _, keyColumn := range keyColumns {
+			if value := row[keyColumn]; value != nil {
+				keyValues = append(keyValues, *value)
+			}
+		}
+		data[DataRowKey(keyValues)] = row
+	}
+
+	return data, rows.Err()
+}
+
+func dataValueToString(value interface{}) *string {
+	var str string
+	switch v := value.(type) {
+	case nil:
+		return nil
+	case []byte:
+		str = string(v)
+	case time.Time:
+		if v.Hour() == 0 && v.Minute() == 0 && v.Second() == 0 && v.Nanosecond() == 0 {
+			str = v.Format("2006-01-02")
+		} else {
+			str = v.Format("2006-01-02 15:04:05")
+		}
+	default:
+		str = fmt.Sprint(v)
+	}
+
+	return &str
+}
+
+// DependencyOrder sorts names so that every name goes after the names it depends on,
+// cyclic dependencies are resolved by name order.
+func DependencyOrder(dependencies map[string][]string) []string {
+	var (
+		names   []string
+		ordered []string
+	)
+	for name := range dependencies {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	visited := make(map[string]bool)
+	var visit func(name string)
+	visit = func(name string) {
+		if visited[name] {
+			return
+		}
+		visited[name] = true
+		deps := append([]string{}, dependencies[name]...)
+		sort.Strings(deps)
+		for _, dep := range deps {
+			if _, ok := dependencies[dep]; ok {
+				visit(dep)
+			}
+		}
+		ordered = append(ordered, name)
+	}
+	for _, name := range names {
+		visit(name)
+	}
+
+	return ordered
+}
+
+func MergeDataTables(sources ...[]string) []string {
+	var tables []string
+	seen := make(map[string]bool)
+	for _, source := range sources {
+		for _, table := range source {
+			if table != "" && !seen[table] {
+				seen[table] = true
+				tables = append(tables, table)
+			}
+		}
+	}
+	sort.Strings(tables)
+
+	return tables
+}
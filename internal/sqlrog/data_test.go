@@ -0,0 +1,106 @@
+package sqlrog
+
+import (
+	"strings"
+	"testing"
+)
+
+type testDataTable struct {
+	name       string
+	data       DataRows
+	columns    []string
+	keys       []string
+	references []string
+}
+
+func (tt *testDataTable) GetName() string             { return tt.name }
+func (tt *testDataTable) GetData() DataRows           { return tt.data }
+func (tt *testDataTable) SetData(data DataRows)       { tt.data = data }
+func (tt *testDataTable) ReferencedTables() []string  { return tt.references }
+func (tt *testDataTable) DataColumns() []string       { return tt.columns }
+func (tt *testDataTable) PrimaryKeyColumns() []string { return tt.keys }
+
+var testDataDialect = &DataDialect{
+	Quoter: NewIdentifierQuoter(`"`, `^[a-z_]+$`, []string{"ORDER"}),
+	StringLiteral: func(value string) string {
+		return "'" + strings.Replace(value, "'", "''", -1) + "'"
+	},
+}
+
+func dataValue(s string) *string {
+	return &s
+}
+
+func TestDataRowKey(t *testing.T) {
+	if DataRowKey([]string{"a,b", "c"}) == DataRowKey([]string{"a", "b,c"}) {
+		t.Errorf("Expected composite keys with commas not to collide\n")
+	}
+	if DataRowKey([]string{`a\`, "b"}) == DataRowKey([]string{"a", `\b`}) {
+		t.Errorf("Expected composite keys with backslashes not to collide\n")
+	}
+	if key := DataRowKey([]string{"1", "en"}); key != "1,en" {
+		t.Errorf("Expected plain keys to stay readable, got %s\n", key)
+	}
+}
+
+func TestTableDataDefinition(t *testing.T) {
+	source := NewTableData([]DataTable{
+		&testDataTable{
+			name:       "order",
+			columns:    []string{"id", "customer_id", "note"},
+			keys:       []string{"id"},
+			references: []string{"customers"},
+			data: DataRows{
+				"1": {"id": dataValue("1"), "customer_id": dataValue("1"), "note": dataValue("it's")},
+				"2": {"id": dataValue("2"), "customer_id": dataValue("2"), "note": nil, "total": dataValue("5")},
+			},
+		},
+		&testDataTable{
+			name:    "customers",
+			columns: []string{"id", "name"},
+			keys:    []string{"id"},
+			data:    DataRows{"1": {"id": dataValue("1"), "name": dataValue("Ann")}, "2": {"id": dataValue("2"), "name": dataValue("Bob")}},
+		},
+		&testDataTable{name: "untracked"},
+	}, testDataDialect)
+	target := NewTableData([]DataTable{
+		&testDataTable{
+			name:       "order",
+			columns:    []string{"id", "customer_id", "note"},
+			keys:       []string{"id"},
+			references: []string{"customers"},
+			data: DataRows{
+				// a derived column of the row differs only, so there is nothing to update
+				"2": {"id": dataValue("2"), "customer_id": dataValue("2"), "note": nil, "total": dataValue("4")},
+				"3": {"id": dataValue("3"), "customer_id": dataValue("3"), "note": nil},
+			},
+		},
+		&testDataTable{
+			name:    "customers",
+			columns: []string{"id", "name"},
+			keys:    []string{"id"},
+			data:    DataRows{"1": {"id": dataValue("1"), "name": dataValue("Anna")}, "3": {"id": dataValue("3"), "name": dataValue("Cid")}},
+		},
+	}, testDataDialect)
+	if _, ok := source.Tables["untracked"]; ok {
+		t.Errorf("Expected tables without data not to be tracked\n")
+	}
+
+	diff := DataDiff(source, target, 6)
+	if diff == nil {
+		t.Fatal("Expected data diff is missing")
+	}
+	expected := strings.Join([]string{
+		`DELETE FROM "order" WHERE id = '3';`,
+		`DELETE FROM customers WHERE id = '3';`,
+		`UPDATE customers SET name = 'Ann' WHERE id = '1';`,
+		`INSERT INTO customers (id,name) VALUES ('2','Bob');`,
+		`INSERT INTO "order" (id,customer_id,note) VALUES ('1','1','it''s');`,
+	}, "\n")
+	if sqls := strings.Join(diff.DiffSql(DEFAULT_SQL_SEP), "\n"); sqls != expected {
+		t.Errorf("Unexpected data sql:\n%s\nexpected:\n%s\n", sqls, expected)
+	}
+	if DataDiff(source, source, 6) != nil {
+		t.Errorf("Expected no diff for equal data\n")
+	}
+}
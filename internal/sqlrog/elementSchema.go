@@ -1,12 +1,15 @@
 package sqlrog
 
-import "database/sql"
+import (
+	"context"
+	"database/sql"
+)
 
 type ElementSchema interface {
 	GetName() string
 	GetTypeName() string
 	GetPluralTypeName() string
-	FetchElementsFromDB(conn *sql.DB) ([]ElementSchema, error)
+	FetchElementsFromDB(ctx context.Context, conn *sql.DB, filter *ElementFilter) ([]ElementSchema, error)
 	AddChild(child ElementSchema) error
 	GetChilds() []ElementSchema
 	CreateDefinition(separator string) []string
@@ -33,7 +36,7 @@ func (be *BaseElementSchema) GetChilds() []ElementSchema {
 func (be *BaseElementSchema) GetGlobalChildElements() []ElementSchema {
 	return nil
 }
-func (be *BaseElementSchema) FetchElementsFromDB(conn *sql.DB) ([]ElementSchema, error) {
+func (be *BaseElementSchema) FetchElementsFromDB(ctx context.Context, conn *sql.DB, filter *ElementFilter) ([]ElementSchema, error) {
 	return nil, nil
 }
 func (be *BaseElementSchema) GetName() string {
@@ -1,6 +1,7 @@
 package sqlrog
 
 import (
+	"context"
 	"fmt"
 	"io/ioutil"
 	"os"
@@ -21,13 +22,28 @@ const (
 type Engine interface {
 	GetName() string
 	CreateParams() interface{}
-	LoadSchema(config *Config, reader ObjectReader) (ElementSchema, error)
+	LoadSchema(ctx context.Context, config *Config, reader ObjectReader, filter *ElementFilter) (ElementSchema, error)
 	SaveSchemaToFiles(config *Config, schema ElementSchema, writer ObjectWriter) error
 	SaveElementSchemaToFile(config *Config, schema ElementSchema, writer ObjectWriter) error
 	DeleteElementSchemaFile(config *Config, schema ElementSchema) error
-	ExecuteSQL(config *Config, sqls []string) error
-	ApplyDiffs(config *Config, diffs []*DiffObject, sep string) error
+	ExecuteSQL(ctx context.Context, config *Config, sqls []string) error
+	ApplyDiffs(ctx context.Context, config *Config, diffs []*DiffObject, sep string) error
 	SchemaDiff(src interface{}, dest interface{}) []*DiffObject
+	ValidateSchema(ctx context.Context, config *Config, reader ObjectReader) ([]*ValidationError, error)
+	GetLintRules() []*LintRule
+	ScriptRenderer() *ScriptRenderer
+	ScriptParser() *ScriptParser
+}
+
+// StatementError is returned by ExecuteSQL, Index is the position of the failed statement, so callers
+// know how many statements were executed.
+type StatementError struct {
+	Index int
+	Err   error
+}
+
+func (e *StatementError) Error() string {
+	return e.Err.Error()
 }
 
 type CoreEngine struct {
@@ -45,31 +61,57 @@ type ObjectReader interface {
 	Read(string) ([]byte, error)
 }
 
-func (e *CoreEngine) LoadElementsFromFiles(appName string, schema ElementSchema, reader ObjectReader) ([]ElementSchema, error) {
+func (e *CoreEngine) LoadElementsFromFiles(appName string, schema ElementSchema, reader ObjectReader, filter *ElementFilter) ([]ElementSchema, error) {
 	var elements []ElementSchema
 	for _, el := range schema.GetGlobalChildElements() {
-		elType := reflect.TypeOf(el).Elem()
-		files, err := ioutil.ReadDir("./" + appName + "/" + el.GetPluralTypeName())
+		typeElements, err := e.LoadOptionalElementTypeFromFiles(appName, el, reader, filter)
 		if err != nil {
 			return nil, err
 		}
-		for _, f := range files {
-			newElement := reflect.New(elType)
-			element := newElement.Interface().(ElementSchema)
-			data, err := reader.Read("./" + appName + "/" + el.GetPluralTypeName() + "/" + f.Name())
-			if err != nil {
-				return nil, err
-			}
-			err = yaml.Unmarshal(data, element)
-			if err != nil {
-				return nil, err
-			}
-			elements = append(elements, element)
+		elements = append(elements, typeElements...)
+	}
+	return elements, nil
+}
+
+func (e *CoreEngine) LoadElementTypeFromFiles(appName string, el ElementSchema, reader ObjectReader, filter *ElementFilter) ([]ElementSchema, error) {
+	var elements []ElementSchema
+	elType := reflect.TypeOf(el).Elem()
+	files, err := ioutil.ReadDir("./" + appName + "/" + el.GetPluralTypeName())
+	if err != nil {
+		return nil, err
+	}
+	for _, f := range files {
+		if IsHookFile(f.Name()) {
+			continue
+		}
+		newElement := reflect.New(elType)
+		element := newElement.Interface().(ElementSchema)
+		data, err := reader.Read("./" + appName + "/" + el.GetPluralTypeName() + "/" + f.Name())
+		if err != nil {
+			return nil, err
+		}
+		err = yaml.Unmarshal(data, element)
+		if err != nil {
+			return nil, err
 		}
+		if !filter.IsMatched(element.GetName()) {
+			continue
+		}
+		elements = append(elements, element)
 	}
 	return elements, nil
 }
 
+// LoadOptionalElementTypeFromFiles skips the type when its folder is missing, e.g. when the project was
+// saved without elements of the type.
+func (e *CoreEngine) LoadOptionalElementTypeFromFiles(appName string, el ElementSchema, reader ObjectReader, filter *ElementFilter) ([]ElementSchema, error) {
+	if _, err := os.Stat("./" + appName + "/" + el.GetPluralTypeName()); os.IsNotExist(err) {
+		return nil, nil
+	}
+
+	return e.LoadElementTypeFromFiles(appName, el, reader, filter)
+}
+
 func (c *CoreEngine) SaveSchemaToFiles(config *Config, schema ElementSchema, writer ObjectWriter) error {
 	for _, element := range schema.GetChilds() {
 		err := c.SaveElementSchemaToFile(config, element, writer)
@@ -107,16 +149,27 @@ func (e *CoreEngine) CompareScheme(source interface{}, target interface{}) []*Di
 	return changes
 }
 
-func (e *CoreEngine) ApplyDiffs(config *Config, diffs []*DiffObject, sep string) error {
+func (e *CoreEngine) ApplyDiffs(ctx context.Context, config *Config, diffs []*DiffObject, sep string) error {
 	fmt.Println("Applying updates...")
 	if config.AppType == ProjectTypeFile {
 		for _, diff := range diffs {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			if diff.SqlOnly {
+				continue
+			}
 			switch diff.State {
-			case DIFF_TYPE_CREATE, DIFF_TYPE_UPDATE:
+			case DIFF_TYPE_CREATE:
 				err := Engines[config.Engine].SaveElementSchemaToFile(config, diff.To, &YamlSchemaWriter{})
 				if err != nil {
 					return err
 				}
+			case DIFF_TYPE_UPDATE:
+				err := Engines[config.Engine].SaveElementSchemaToFile(config, diff.From, &YamlSchemaWriter{})
+				if err != nil {
+					return err
+				}
 			case DIFF_TYPE_DROP:
 				err := Engines[config.Engine].DeleteElementSchemaFile(config, diff.From)
 				if err != nil {
@@ -125,15 +178,34 @@ func (e *CoreEngine) ApplyDiffs(config *Config, diffs []*DiffObject, sep string)
 			}
 		}
 	} else {
+		applied := 0
 		for _, diff := range diffs {
-			for _, stmt := range diff.DiffSql(sep) {
-				Logln("info", "Applying: ...")
+			if err := ctx.Err(); err != nil {
+				Logln("warn", fmt.Sprintf("Apply is cancelled after %d statement(s)", applied))
+				return err
+			}
+			// statements of one diff run in the same session, so SET statements apply to the rest of them
+			stmts := diff.DiffSql(sep)
+			if len(stmts) == 0 {
+				continue
+			}
+			Logln("info", "Applying: ...")
+			for _, stmt := range stmts {
 				Logln("info", stmt)
-				if err := Engines[config.Engine].ExecuteSQL(config, []string{stmt}); err != nil {
-					return err
+			}
+			if err := Engines[config.Engine].ExecuteSQL(ctx, config, stmts); err != nil {
+				if statementErr, ok := err.(*StatementError); ok {
+					applied += statementErr.Index
 				}
-				Logln("info", "Done\n")
+				if ctx.Err() != nil {
+					Logln("warn", fmt.Sprintf("Apply is cancelled after %d statement(s)", applied))
+				} else {
+					Logln("warn", fmt.Sprintf("Apply is stopped on error after %d statement(s)", applied))
+				}
+				return err
 			}
+			applied += len(stmts)
+			Logln("info", "Done\n")
 		}
 	}
 
@@ -150,7 +222,7 @@ func (c *CoreEngine) SaveElementSchemaToFile(config *Config, element ElementSche
 		}
 	}
 
-	err := writer.Write(element, path+"/"+strings.Trim(element.GetName(), " ")+".yaml")
+	err := writer.Write(element, c.ElementFilePath(appName, element))
 	if err != nil {
 		return err
 	}
@@ -159,8 +231,7 @@ func (c *CoreEngine) SaveElementSchemaToFile(config *Config, element ElementSche
 }
 
 func (c *CoreEngine) DeleteElementSchemaFile(config *Config, element ElementSchema) error {
-	appName := config.GetAppName()
-	path := "./" + appName + "/" + element.GetPluralTypeName() + "/" + strings.Trim(element.GetName(), " ") + ".yaml"
+	path := c.ElementFilePath(config.GetAppName(), element)
 	if _, err := os.Stat(path); err == nil {
 		return os.Remove(path)
 	}
@@ -168,6 +239,10 @@ func (c *CoreEngine) DeleteElementSchemaFile(config *Config, element ElementSche
 	return nil
 }
 
+func (c *CoreEngine) ElementFilePath(appName string, element ElementSchema) string {
+	return "./" + appName + "/" + element.GetPluralTypeName() + "/" + strings.Trim(element.GetName(), " ") + ".yaml"
+}
+
 func (e *CoreEngine) CastInterfaceToMapElementSchema(source interface{}) map[string]ElementSchema {
 	src := make(map[string]ElementSchema)
 	v := reflect.ValueOf(source)
@@ -208,6 +283,7 @@ type DiffObject struct {
 	From     ElementSchema
 	To       ElementSchema
 	Priority int
+	SqlOnly  bool
 }
 
 func (o *DiffObject) DiffSql(sep string) []string {
@@ -0,0 +1,57 @@
+package sqlrog
+
+import (
+	"context"
+	"testing"
+)
+
+type testElement struct {
+	BaseElementSchema
+	Name string
+}
+
+func (te *testElement) GetName() string {
+	return te.Name
+}
+
+// fileEngine records elements saved to and deleted from a file project.
+type fileEngine struct {
+	Engine
+	saved   []ElementSchema
+	deleted []ElementSchema
+}
+
+func (fe *fileEngine) SaveElementSchemaToFile(config *Config, schema ElementSchema, writer ObjectWriter) error {
+	fe.saved = append(fe.saved, schema)
+	return nil
+}
+
+func (fe *fileEngine) DeleteElementSchemaFile(config *Config, schema ElementSchema) error {
+	fe.deleted = append(fe.deleted, schema)
+	return nil
+}
+
+func TestApplyDiffsToFiles(t *testing.T) {
+	engine := &fileEngine{}
+	Engines["test"] = engine
+	defer delete(Engines, "test")
+
+	source, target := &testElement{Name: "source"}, &testElement{Name: "target"}
+	created, dropped := &testElement{Name: "created"}, &testElement{Name: "dropped"}
+	diffs := []*DiffObject{
+		{State: DIFF_TYPE_UPDATE, From: source, To: target},
+		{State: DIFF_TYPE_CREATE, To: created},
+		{State: DIFF_TYPE_DROP, From: dropped},
+	}
+	config := &Config{AppType: ProjectTypeFile, Engine: "test"}
+	if err := (&CoreEngine{}).ApplyDiffs(context.Background(), config, diffs, DEFAULT_SQL_SEP); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(engine.saved) != 2 || engine.saved[0] != source || engine.saved[1] != created {
+		t.Errorf("Expected the updated source and the created element to be saved, got %v\n", engine.saved)
+	}
+	if len(engine.deleted) != 1 || engine.deleted[0] != dropped {
+		t.Errorf("Expected the dropped element to be deleted, got %v\n", engine.deleted)
+	}
+}
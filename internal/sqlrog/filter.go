@@ -0,0 +1,54 @@
+package sqlrog
+
+import "strings"
+
+type ElementFilter struct {
+	Match  string
+	Ignore []string
+}
+
+func (f *ElementFilter) IsEmpty() bool {
+	return f == nil || (f.Match == "" && len(f.Ignore) == 0)
+}
+
+func (f *ElementFilter) IsMatched(name string) bool {
+	if f.IsEmpty() {
+		return true
+	}
+	name = strings.ToUpper(name)
+	if f.Match != "" && !strings.Contains(name, strings.ToUpper(f.Match)) {
+		return false
+	}
+	for _, ignore := range f.Ignore {
+		if ignore != "" && strings.Contains(name, strings.ToUpper(ignore)) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Condition builds an additional "AND ..." clause for catalog queries. Engines pass their own
+// templates for substring matching, e.g. "LOCATE(UPPER(?), UPPER(%s)) > 0" for MySQL.
+func (f *ElementFilter) Condition(column string, matchTmpl string, ignoreTmpl string) (string, []interface{}) {
+	var (
+		condition string
+		args      []interface{}
+	)
+	if f.IsEmpty() {
+		return condition, args
+	}
+	if f.Match != "" {
+		condition += " AND " + strings.Replace(matchTmpl, "%s", column, -1)
+		args = append(args, f.Match)
+	}
+	for _, ignore := range f.Ignore {
+		if ignore == "" {
+			continue
+		}
+		condition += " AND " + strings.Replace(ignoreTmpl, "%s", column, -1)
+		args = append(args, ignore)
+	}
+
+	return condition, args
+}
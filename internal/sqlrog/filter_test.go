@@ -0,0 +1,41 @@
+package sqlrog
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestElementFilterIsMatched(t *testing.T) {
+	filter := &ElementFilter{Match: "car", Ignore: []string{"TMP", ""}}
+	cases := map[string]bool{
+		"CARS":         true,
+		"sport_cars":   true,
+		"cars_tmp":     false,
+		"categories":   false,
+		"tmp_carriers": false,
+	}
+	for name, expected := range cases {
+		if matched := filter.IsMatched(name); matched != expected {
+			t.Errorf("IsMatched(%q) = %v, expected %v\n", name, matched, expected)
+		}
+	}
+	var empty *ElementFilter
+	if !empty.IsEmpty() || !empty.IsMatched("anything") {
+		t.Errorf("Expected a nil filter to match everything\n")
+	}
+}
+
+func TestElementFilterCondition(t *testing.T) {
+	filter := &ElementFilter{Match: "car", Ignore: []string{"tmp", ""}}
+	condition, args := filter.Condition("t.name", "LOCATE(?, %s) > 0", "NOT %s LIKE ?")
+
+	if expected := " AND LOCATE(?, t.name) > 0 AND NOT t.name LIKE ?"; condition != expected {
+		t.Errorf("Unexpected condition:\n%s\nexpected:\n%s\n", condition, expected)
+	}
+	if !reflect.DeepEqual(args, []interface{}{"car", "tmp"}) {
+		t.Errorf("Unexpected arguments: %v\n", args)
+	}
+	if condition, args := (&ElementFilter{}).Condition("t.name", "%s", "%s"); condition != "" || len(args) != 0 {
+		t.Errorf("Expected no condition for an empty filter, got %q %v\n", condition, args)
+	}
+}
@@ -0,0 +1,63 @@
+package sqlrog
+
+import (
+	"sort"
+	"strings"
+)
+
+// Privilege is a single permission of a grantee on a database object or column.
+type Privilege struct {
+	Privilege   string `yaml:"privilege"`
+	ObjectType  string `yaml:"object_type"`
+	Object      string `yaml:"object"`
+	Column      string `yaml:"column,omitempty"`
+	GrantOption bool   `yaml:"grant_option,omitempty"`
+}
+
+func (p *Privilege) Key() string {
+	return strings.Join([]string{p.ObjectType, p.Object, p.Column, p.Privilege}, ".")
+}
+
+func (p *Privilege) Equals(other *Privilege) bool {
+	return p.Key() == other.Key() && p.GrantOption == other.GrantOption
+}
+
+func SortPrivileges(privileges []*Privilege) {
+	sort.Slice(privileges, func(i, j int) bool {
+		return privileges[i].Key() < privileges[j].Key()
+	})
+}
+
+func PrivilegesEqual(src []*Privilege, dest []*Privilege) bool {
+	added, removed := PrivilegesDiff(src, dest)
+
+	return len(added) == 0 && len(removed) == 0
+}
+
+// PrivilegesDiff returns privileges to grant and to revoke to turn dest into src,
+// a privilege with changed grant option is revoked and granted again.
+func PrivilegesDiff(src []*Privilege, dest []*Privilege) ([]*Privilege, []*Privilege) {
+	var added, removed []*Privilege
+	srcByKey := make(map[string]*Privilege)
+	for _, privilege := range src {
+		srcByKey[privilege.Key()] = privilege
+	}
+	destByKey := make(map[string]*Privilege)
+	for _, privilege := range dest {
+		destByKey[privilege.Key()] = privilege
+	}
+	for key, privilege := range destByKey {
+		if other, ok := srcByKey[key]; !ok || !other.Equals(privilege) {
+			removed = append(removed, privilege)
+		}
+	}
+	for key, privilege := range srcByKey {
+		if other, ok := destByKey[key]; !ok || !other.Equals(privilege) {
+			added = append(added, privilege)
+		}
+	}
+	SortPrivileges(added)
+	SortPrivileges(removed)
+
+	return added, removed
+}
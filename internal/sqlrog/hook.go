@@ -0,0 +1,225 @@
+package sqlrog
+
+import (
+	"io/ioutil"
+	"os"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	CORE_ELEMENT_HOOK_NAME = "hook"
+	HOOK_PRE               = "pre"
+	HOOK_POST              = "post"
+)
+
+// Hook is a script of a file project which runs before or after the changes: globally from pre/ and post/
+// folders or around changes of one element, e.g. tables/cars.pre.sql.
+type Hook struct {
+	BaseElementSchema `yaml:"base,omitempty"`
+	Path              string
+	Statements        []string
+}
+
+func (h *Hook) GetName() string {
+	return h.Path
+}
+
+func (h *Hook) GetTypeName() string {
+	return CORE_ELEMENT_HOOK_NAME
+}
+
+func (h *Hook) CreateDefinition(sep string) []string {
+	definitions := make([]string, len(h.Statements))
+	for i, stmt := range h.Statements {
+		definitions[i] = stmt + sep
+	}
+
+	return definitions
+}
+
+type Hooks struct {
+	Pre  []*Hook
+	Post []*Hook
+	// element hooks are keyed by plural type name and element name, e.g. tables/cars
+	ElementPre  map[string]*Hook
+	ElementPost map[string]*Hook
+}
+
+// HookChild is implemented by elements saved in the file of another element, e.g. indexes and triggers
+// of a table, so hooks of that element surround their changes too.
+type HookChild interface {
+	// HookParentKey returns the plural type name and name of the parent element, e.g. tables/cars,
+	// or empty string when the element has hooks of its own.
+	HookParentKey() string
+}
+
+func IsHookFile(fileName string) bool {
+	return strings.HasSuffix(fileName, "."+HOOK_PRE+".sql") || strings.HasSuffix(fileName, "."+HOOK_POST+".sql")
+}
+
+// LoadHooks reads hook scripts of the file project, element hooks are looked up in folders of the schema element types.
+func LoadHooks(appName string, schema ElementSchema, parser *ScriptParser) (*Hooks, error) {
+	hooks := &Hooks{
+		ElementPre:  make(map[string]*Hook),
+		ElementPost: make(map[string]*Hook),
+	}
+	var err error
+	if hooks.Pre, err = loadHookFolder("./"+appName+"/"+HOOK_PRE, parser); err != nil {
+		return nil, err
+	}
+	if hooks.Post, err = loadHookFolder("./"+appName+"/"+HOOK_POST, parser); err != nil {
+		return nil, err
+	}
+	for _, el := range schema.GetGlobalChildElements() {
+		dir := "./" + appName + "/" + el.GetPluralTypeName()
+		files, err := ioutil.ReadDir(dir)
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		for _, f := range files {
+			if f.IsDir() || !IsHookFile(f.Name()) {
+				continue
+			}
+			hook, err := loadHook(dir+"/"+f.Name(), parser)
+			if err != nil {
+				return nil, err
+			}
+			name := strings.TrimSuffix(f.Name(), ".sql")
+			if strings.HasSuffix(name, "."+HOOK_PRE) {
+				hooks.ElementPre[el.GetPluralTypeName()+"/"+strings.TrimSuffix(name, "."+HOOK_PRE)] = hook
+			} else {
+				hooks.ElementPost[el.GetPluralTypeName()+"/"+strings.TrimSuffix(name, "."+HOOK_POST)] = hook
+			}
+		}
+	}
+
+	return hooks, nil
+}
+
+func loadHookFolder(dir string, parser *ScriptParser) ([]*Hook, error) {
+	var hooks []*Hook
+	files, err := ioutil.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	// files are sorted by name, so they could be numbered to set the order
+	for _, f := range files {
+		if f.IsDir() || !strings.HasSuffix(f.Name(), ".sql") {
+			continue
+		}
+		hook, err := loadHook(dir+"/"+f.Name(), parser)
+		if err != nil {
+			return nil, err
+		}
+		hooks = append(hooks, hook)
+	}
+
+	return hooks, nil
+}
+
+func loadHook(path string, parser *ScriptParser) (*Hook, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	statements, err := parser.Split(string(data))
+	if err != nil {
+		return nil, errors.Wrap(err, path)
+	}
+	hook := &Hook{Path: path}
+	for _, statement := range statements {
+		hook.Statements = append(hook.Statements, statement.SQL)
+	}
+
+	return hook, nil
+}
+
+// AttachHooks puts hooks of the source project around sorted diffs. Only file projects keep hook scripts,
+// diffs of other projects are returned as is.
+func AttachHooks(engine Engine, config *Config, schema ElementSchema, diffs []*DiffObject) ([]*DiffObject, error) {
+	if config.AppType != ProjectTypeFile {
+		return diffs, nil
+	}
+	hooks, err := LoadHooks(config.GetAppName(), schema, engine.ScriptParser())
+	if err != nil {
+		return nil, err
+	}
+
+	return hooks.Attach(diffs), nil
+}
+
+// Attach puts hooks around sorted diffs: global hooks before and after all changes, element hooks before
+// the first and after the last change of the element or its children. Hooks run only when there is something
+// to change. Hook diffs are SQL only, so ApplyDiffs runs them like other statements once they are attached.
+func (h *Hooks) Attach(diffs []*DiffObject) []*DiffObject {
+	if len(diffs) == 0 {
+		return diffs
+	}
+	first := make(map[string]int)
+	last := make(map[string]int)
+	for i, diff := range diffs {
+		key := diffElementKey(diff)
+		if _, ok := first[key]; !ok {
+			first[key] = i
+		}
+		last[key] = i
+	}
+
+	var attached []*DiffObject
+	for _, hook := range h.Pre {
+		attached = append(attached, hookDiff(hook, diffs[0].Priority))
+	}
+	for i, diff := range diffs {
+		key := diffElementKey(diff)
+		if hook, ok := h.ElementPre[key]; ok && first[key] == i {
+			attached = append(attached, hookDiff(hook, diff.Priority))
+		}
+		attached = append(attached, diff)
+		if hook, ok := h.ElementPost[key]; ok && last[key] == i {
+			attached = append(attached, hookDiff(hook, diff.Priority))
+		}
+	}
+	for _, hook := range h.Post {
+		attached = append(attached, hookDiff(hook, diffs[len(diffs)-1].Priority))
+	}
+
+	return attached
+}
+
+func diffElementKey(diff *DiffObject) string {
+	element := diff.From
+	if diff.State == DIFF_TYPE_CREATE {
+		element = diff.To
+	}
+	if element == nil {
+		return ""
+	}
+	if child, ok := element.(HookChild); ok {
+		if key := child.HookParentKey(); key != "" {
+			return key
+		}
+	}
+	if element.GetPluralTypeName() == "" {
+		return ""
+	}
+
+	return element.GetPluralTypeName() + "/" + strings.Trim(element.GetName(), " ")
+}
+
+func hookDiff(hook *Hook, priority int) *DiffObject {
+	return &DiffObject{
+		State:    DIFF_TYPE_CREATE,
+		Type:     hook.GetTypeName(),
+		To:       hook,
+		Priority: priority,
+		SqlOnly:  true,
+	}
+}
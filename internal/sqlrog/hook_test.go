@@ -0,0 +1,94 @@
+package sqlrog
+
+import (
+	"reflect"
+	"testing"
+)
+
+type hookElement struct {
+	testElement
+	table string
+}
+
+func (he *hookElement) GetPluralTypeName() string {
+	return "tables"
+}
+
+func (he *hookElement) HookParentKey() string {
+	if he.table == "" {
+		return ""
+	}
+
+	return "tables/" + he.table
+}
+
+func hookPaths(diffs []*DiffObject) []string {
+	var paths []string
+	for _, diff := range diffs {
+		if hook, ok := diff.To.(*Hook); ok {
+			paths = append(paths, hook.Path)
+			continue
+		}
+		element := diff.To
+		if element == nil {
+			element = diff.From
+		}
+		paths = append(paths, element.GetName())
+	}
+
+	return paths
+}
+
+func TestHooksAttach(t *testing.T) {
+	hooks := &Hooks{
+		Pre:  []*Hook{{Path: "pre/01.sql"}, {Path: "pre/02.sql"}},
+		Post: []*Hook{{Path: "post/01.sql"}},
+		ElementPre: map[string]*Hook{
+			"tables/cars":   {Path: "tables/cars.pre.sql"},
+			"tables/colors": {Path: "tables/colors.pre.sql"},
+		},
+		ElementPost: map[string]*Hook{
+			"tables/cars": {Path: "tables/cars.post.sql"},
+		},
+	}
+	diffs := []*DiffObject{
+		{State: DIFF_TYPE_CREATE, To: &hookElement{testElement: testElement{Name: "cars"}}, Priority: 10},
+		{State: DIFF_TYPE_UPDATE, From: &hookElement{testElement: testElement{Name: "engines"}}, Priority: 10},
+		{State: DIFF_TYPE_CREATE, To: &hookElement{testElement: testElement{Name: "idx_cars"}, table: "cars"}, Priority: 8},
+		{State: DIFF_TYPE_DROP, From: &hookElement{testElement: testElement{Name: "trg_cars"}, table: "cars"}, Priority: -4},
+	}
+	expected := []string{
+		"pre/01.sql", "pre/02.sql",
+		"tables/cars.pre.sql", "cars",
+		"engines",
+		"idx_cars",
+		"trg_cars", "tables/cars.post.sql",
+		"post/01.sql",
+	}
+	attached := hooks.Attach(diffs)
+	if paths := hookPaths(attached); !reflect.DeepEqual(paths, expected) {
+		t.Errorf("Unexpected order of diffs with hooks:\n%v\nexpected:\n%v\n", paths, expected)
+	}
+	for _, diff := range attached {
+		if _, ok := diff.To.(*Hook); ok && (!diff.SqlOnly || diff.State != DIFF_TYPE_CREATE) {
+			t.Errorf("Expected hooks to be SQL only creates, got %+v\n", diff)
+		}
+	}
+	if attached[0].Priority != 10 || attached[len(attached)-1].Priority != -4 {
+		t.Errorf("Expected global hooks to get priorities of the first and the last diff\n")
+	}
+	if attached := hooks.Attach(nil); len(attached) != 0 {
+		t.Errorf("Expected hooks to be skipped when there is nothing to change, got %v\n", hookPaths(attached))
+	}
+}
+
+func TestAttachHooksOfConnection(t *testing.T) {
+	diffs := []*DiffObject{{State: DIFF_TYPE_CREATE, To: &hookElement{testElement: testElement{Name: "cars"}}}}
+	attached, err := AttachHooks(nil, &Config{AppType: "connection"}, nil, diffs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(attached, diffs) {
+		t.Errorf("Expected diffs of a connection project to be left as is, got %v\n", hookPaths(attached))
+	}
+}
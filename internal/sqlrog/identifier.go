@@ -0,0 +1,48 @@
+package sqlrog
+
+import (
+	"regexp"
+	"strings"
+)
+
+// IdentifierQuoter quotes names which can't be used in SQL as they are: reserved words
+// and names not matching the regular identifier pattern of the engine.
+type IdentifierQuoter struct {
+	Quote    string
+	Regular  *regexp.Regexp
+	Reserved map[string]bool
+}
+
+func NewIdentifierQuoter(quote string, regular string, reserved []string) *IdentifierQuoter {
+	q := &IdentifierQuoter{
+		Quote:    quote,
+		Regular:  regexp.MustCompile(regular),
+		Reserved: make(map[string]bool),
+	}
+	for _, word := range reserved {
+		q.Reserved[word] = true
+	}
+
+	return q
+}
+
+func (q *IdentifierQuoter) NeedsQuoting(name string) bool {
+	return !q.Regular.MatchString(name) || q.Reserved[strings.ToUpper(name)]
+}
+
+func (q *IdentifierQuoter) QuoteIdentifier(name string) string {
+	if !q.NeedsQuoting(name) {
+		return name
+	}
+
+	return q.Quote + strings.Replace(name, q.Quote, q.Quote+q.Quote, -1) + q.Quote
+}
+
+func (q *IdentifierQuoter) QuoteIdentifiers(names []string) []string {
+	quoted := make([]string, len(names))
+	for i, name := range names {
+		quoted[i] = q.QuoteIdentifier(name)
+	}
+
+	return quoted
+}
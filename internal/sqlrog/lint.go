@@ -0,0 +1,316 @@
+package sqlrog
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"regexp"
+	"sort"
+
+	"gopkg.in/yaml.v2"
+)
+
+const (
+	LINT_SEVERITY_IGNORE  = "ignore"
+	LINT_SEVERITY_INFO    = "info"
+	LINT_SEVERITY_WARNING = "warning"
+	LINT_SEVERITY_ERROR   = "error"
+	LINT_RULE_NAMING      = "naming_convention"
+)
+
+type LintRule struct {
+	Name        string
+	Description string
+	Severity    string
+	Check       func(schema ElementSchema, config *LintConfig) []*LintIssue
+}
+
+type LintIssue struct {
+	Rule     string `json:"rule"`
+	Severity string `json:"severity"`
+	Type     string `json:"type"`
+	Element  string `json:"element"`
+	Message  string `json:"message"`
+}
+
+func (li *LintIssue) String() string {
+	return fmt.Sprintf("[%s] %s %s: %s (%s)", li.Severity, li.Type, li.Element, li.Message, li.Rule)
+}
+
+type LintConfig struct {
+	Charset    string            `yaml:"charset"`
+	Severities map[string]string `yaml:"severities"`
+	Naming     map[string]string `yaml:"naming"`
+	naming     map[string]*regexp.Regexp
+}
+
+// LintChild is a nested element which name is checked by the naming rule, like a table column.
+type LintChild struct {
+	Type string
+	Name string
+}
+
+// LintChildren is implemented by elements with nested elements, e.g. tables with columns, indexes and triggers.
+type LintChildren interface {
+	LintChildren() []*LintChild
+}
+
+func (lc *LintConfig) Load(fileName string) error {
+	if _, err := os.Stat(fileName); os.IsNotExist(err) {
+		return nil
+	}
+	data, err := ioutil.ReadFile(fileName)
+	if err != nil {
+		return err
+	}
+
+	if err = yaml.Unmarshal(data, lc); err != nil {
+		return err
+	}
+
+	return lc.CompileNaming()
+}
+
+// CompileNaming compiles naming patterns once, an invalid pattern is a config error.
+func (lc *LintConfig) CompileNaming() error {
+	lc.naming = make(map[string]*regexp.Regexp)
+	for elementType, pattern := range lc.Naming {
+		compiled, err := regexp.Compile(pattern)
+		if err != nil {
+			return errors.New(fmt.Sprintf("Naming pattern for %s is invalid: %s", elementType, err))
+		}
+		lc.naming[elementType] = compiled
+	}
+
+	return nil
+}
+
+func (lc *LintConfig) GetSeverity(rule *LintRule) (string, error) {
+	severity, ok := lc.Severities[rule.Name]
+	if !ok {
+		return rule.Severity, nil
+	}
+	switch severity {
+	case LINT_SEVERITY_IGNORE, LINT_SEVERITY_INFO, LINT_SEVERITY_WARNING, LINT_SEVERITY_ERROR:
+		return severity, nil
+	}
+
+	return "", errors.New(fmt.Sprintf("Unknown severity '%s' for rule %s", severity, rule.Name))
+}
+
+func LintSchema(rules []*LintRule, schema ElementSchema, config *LintConfig) ([]*LintIssue, error) {
+	var issues []*LintIssue
+	if config.naming == nil {
+		if err := config.CompileNaming(); err != nil {
+			return nil, err
+		}
+	}
+	for _, rule := range append([]*LintRule{NamingLintRule()}, rules...) {
+		severity, err := config.GetSeverity(rule)
+		if err != nil {
+			return nil, err
+		}
+		if severity == LINT_SEVERITY_IGNORE {
+			continue
+		}
+		for _, issue := range rule.Check(schema, config) {
+			issue.Rule = rule.Name
+			issue.Severity = severity
+			issues = append(issues, issue)
+		}
+	}
+	sort.SliceStable(issues, func(i, j int) bool {
+		if issues[i].Type != issues[j].Type {
+			return issues[i].Type < issues[j].Type
+		}
+		if issues[i].Element != issues[j].Element {
+			return issues[i].Element < issues[j].Element
+		}
+		return issues[i].Message < issues[j].Message
+	})
+
+	return issues, nil
+}
+
+func NamingLintRule() *LintRule {
+	return &LintRule{
+		Name:        LINT_RULE_NAMING,
+		Description: "Element names should match the configured regular expression for their type",
+		Severity:    LINT_SEVERITY_WARNING,
+		Check: func(schema ElementSchema, config *LintConfig) []*LintIssue {
+			var issues []*LintIssue
+			check := func(elementType string, name string, element string) {
+				pattern, ok := config.naming[elementType]
+				if ok && !pattern.MatchString(name) {
+					issues = append(issues, &LintIssue{
+						Type:    elementType,
+						Element: element,
+						Message: fmt.Sprintf("name doesn't match '%s'", pattern),
+					})
+				}
+			}
+			for _, element := range schema.GetChilds() {
+				check(element.GetTypeName(), element.GetName(), element.GetName())
+				if parent, ok := element.(LintChildren); ok {
+					for _, child := range parent.LintChildren() {
+						check(child.Type, child.Name, element.GetName()+"."+child.Name)
+					}
+				}
+			}
+			return issues
+		},
+	}
+}
+
+var selectStarRegexp = regexp.MustCompile(`(?i)\bselect\s+(distinct\s+)?(first\s+\S+\s+)?(skip\s+\S+\s+)?(\w+\.)?\*`)
+
+// LintTable is a table as seen by the lint rules shared by engines.
+type LintTable interface {
+	ElementSchema
+	HasPrimaryKey() bool
+	// LintColumns returns columns ordered by position.
+	LintColumns() []*LintColumn
+	// LintCharsets returns charsets the database charset is guessed from when it isn't configured.
+	LintCharsets() []string
+	// LintUniqueIndexes returns indexes which fields must be unique.
+	LintUniqueIndexes() []*LintIndex
+}
+
+type LintColumn struct {
+	Name    string
+	Charset string
+	NotNull bool
+}
+
+type LintIndex struct {
+	Name   string
+	Fields []string
+}
+
+// LintSource is implemented by routines, their source is checked for SELECT *.
+type LintSource interface {
+	LintSource() string
+}
+
+func LintTables(schema ElementSchema) []LintTable {
+	var tables []LintTable
+	for _, element := range schema.GetChilds() {
+		if table, ok := element.(LintTable); ok {
+			tables = append(tables, table)
+		}
+	}
+
+	return tables
+}
+
+func TableLintIssue(table ElementSchema, message string) *LintIssue {
+	return &LintIssue{
+		Type:    table.GetTypeName(),
+		Element: table.GetName(),
+		Message: message,
+	}
+}
+
+// TableLintRules are the rules shared by engines, engines add their own ones to them.
+func TableLintRules() []*LintRule {
+	return []*LintRule{
+		{
+			Name:        "no_primary_key",
+			Description: "Tables should have a primary key",
+			Severity:    LINT_SEVERITY_ERROR,
+			Check: func(schema ElementSchema, config *LintConfig) []*LintIssue {
+				var issues []*LintIssue
+				for _, table := range LintTables(schema) {
+					if !table.HasPrimaryKey() {
+						issues = append(issues, TableLintIssue(table, "table has no primary key"))
+					}
+				}
+				return issues
+			},
+		},
+		{
+			Name:        "column_charset",
+			Description: "Column charsets should match the database charset",
+			Severity:    LINT_SEVERITY_WARNING,
+			Check: func(schema ElementSchema, config *LintConfig) []*LintIssue {
+				var issues []*LintIssue
+				tables := LintTables(schema)
+				charset := config.Charset
+				if charset == "" {
+					var charsets []string
+					for _, table := range tables {
+						charsets = append(charsets, table.LintCharsets()...)
+					}
+					charset = PredominantValue(charsets)
+				}
+				for _, table := range tables {
+					for _, column := range table.LintColumns() {
+						if column.Charset != "" && column.Charset != charset {
+							issues = append(issues, TableLintIssue(table, fmt.Sprintf("column %s uses %s charset in %s database", column.Name, column.Charset, charset)))
+						}
+					}
+				}
+				return issues
+			},
+		},
+		{
+			Name:        "nullable_unique_column",
+			Description: "Columns of unique indexes should be NOT NULL",
+			Severity:    LINT_SEVERITY_WARNING,
+			Check: func(schema ElementSchema, config *LintConfig) []*LintIssue {
+				var issues []*LintIssue
+				for _, table := range LintTables(schema) {
+					columns := make(map[string]*LintColumn)
+					for _, column := range table.LintColumns() {
+						columns[column.Name] = column
+					}
+					for _, index := range table.LintUniqueIndexes() {
+						for _, field := range index.Fields {
+							if column, ok := columns[field]; ok && !column.NotNull {
+								issues = append(issues, TableLintIssue(table, fmt.Sprintf("column %s of unique index %s is nullable", field, index.Name)))
+							}
+						}
+					}
+				}
+				return issues
+			},
+		},
+		{
+			Name:        "select_star",
+			Description: "Routines should list selected columns explicitly",
+			Severity:    LINT_SEVERITY_WARNING,
+			Check: func(schema ElementSchema, config *LintConfig) []*LintIssue {
+				var issues []*LintIssue
+				for _, element := range schema.GetChilds() {
+					if routine, ok := element.(LintSource); ok && selectStarRegexp.MatchString(routine.LintSource()) {
+						issues = append(issues, &LintIssue{
+							Type:    element.GetTypeName(),
+							Element: element.GetName(),
+							Message: "source uses SELECT *",
+						})
+					}
+				}
+				return issues
+			},
+		},
+	}
+}
+
+// PredominantValue returns the most used non empty value, it is used for guessing database
+// wide settings like charset which aren't stored in the schema.
+func PredominantValue(values []string) string {
+	counts := make(map[string]int)
+	var predominant string
+	for _, value := range values {
+		if value == "" {
+			continue
+		}
+		counts[value]++
+		if counts[value] > counts[predominant] || (counts[value] == counts[predominant] && value < predominant) {
+			predominant = value
+		}
+	}
+
+	return predominant
+}
@@ -0,0 +1,104 @@
+package sqlrog
+
+import (
+	"strings"
+	"testing"
+)
+
+type testSchema struct {
+	BaseElementSchema
+	childs []ElementSchema
+}
+
+func (ts *testSchema) GetChilds() []ElementSchema {
+	return ts.childs
+}
+
+type testTable struct {
+	testElement
+	primary bool
+	charset string
+	columns []*LintColumn
+	unique  []*LintIndex
+}
+
+func (tt *testTable) GetTypeName() string             { return "table" }
+func (tt *testTable) HasPrimaryKey() bool             { return tt.primary }
+func (tt *testTable) LintColumns() []*LintColumn      { return tt.columns }
+func (tt *testTable) LintCharsets() []string          { return []string{tt.charset} }
+func (tt *testTable) LintUniqueIndexes() []*LintIndex { return tt.unique }
+func (tt *testTable) LintChildren() []*LintChild {
+	var children []*LintChild
+	for _, column := range tt.columns {
+		children = append(children, &LintChild{Type: "table_column", Name: column.Name})
+	}
+	return children
+}
+
+type testRoutine struct {
+	testElement
+	source string
+}
+
+func (tr *testRoutine) GetTypeName() string { return "procedure" }
+func (tr *testRoutine) LintSource() string  { return tr.source }
+
+func lintOutput(t *testing.T, schema ElementSchema, config *LintConfig) string {
+	issues, err := LintSchema(TableLintRules(), schema, config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var lines []string
+	for _, issue := range issues {
+		lines = append(lines, issue.String())
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+func TestLintSchemaRules(t *testing.T) {
+	schema := &testSchema{childs: []ElementSchema{
+		&testTable{
+			testElement: testElement{Name: "cars"},
+			primary:     true,
+			charset:     "utf8",
+			columns:     []*LintColumn{{Name: "id", NotNull: true}, {Name: "serialNo", Charset: "latin1"}},
+			unique:      []*LintIndex{{Name: "idx_serial", Fields: []string{"serialNo"}}},
+		},
+		&testTable{testElement: testElement{Name: "log"}, charset: "utf8"},
+		&testRoutine{testElement: testElement{Name: "list_cars"}, source: "BEGIN SELECT DISTINCT c.* FROM cars c; END"},
+		&testRoutine{testElement: testElement{Name: "count_cars"}, source: "BEGIN SELECT COUNT(*) FROM cars; END"},
+	}}
+	config := &LintConfig{
+		Severities: map[string]string{"nullable_unique_column": LINT_SEVERITY_ERROR},
+		Naming:     map[string]string{"table_column": "^[a-z_]+$"},
+	}
+
+	expected := strings.Join([]string{
+		"[warning] procedure list_cars: source uses SELECT * (select_star)",
+		"[error] table cars: column serialNo of unique index idx_serial is nullable (nullable_unique_column)",
+		"[warning] table cars: column serialNo uses latin1 charset in utf8 database (column_charset)",
+		"[error] table log: table has no primary key (no_primary_key)",
+		"[warning] table_column cars.serialNo: name doesn't match '^[a-z_]+$' (naming_convention)",
+	}, "\n")
+	if output := lintOutput(t, schema, config); output != expected {
+		t.Errorf("Unexpected lint issues:\n%s\nexpected:\n%s\n", output, expected)
+	}
+
+	config.Severities = map[string]string{"select_star": LINT_SEVERITY_IGNORE, "no_primary_key": LINT_SEVERITY_IGNORE}
+	config.Charset = "latin1"
+	output := lintOutput(t, schema, config)
+	if strings.Contains(output, "select_star") || strings.Contains(output, "no_primary_key") || strings.Contains(output, "column_charset") {
+		t.Errorf("Expected ignored rules and the configured charset to be respected:\n%s\n", output)
+	}
+}
+
+func TestLintConfigErrors(t *testing.T) {
+	schema := &testSchema{}
+	if _, err := LintSchema(nil, schema, &LintConfig{Naming: map[string]string{"table": "^[a-z"}}); err == nil {
+		t.Errorf("Expected an invalid naming pattern to be an error\n")
+	}
+	if _, err := LintSchema(nil, schema, &LintConfig{Severities: map[string]string{LINT_RULE_NAMING: "fatal"}}); err == nil {
+		t.Errorf("Expected an unknown severity to be an error\n")
+	}
+}
@@ -0,0 +1,196 @@
+package sqlrog
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// ScriptRenderer writes statements as a script for the native client of the engine. Statements
+// with semicolons inside (routine bodies, EXECUTE BLOCK) are terminated with the compound
+// delimiter, which is switched on and off with the engine specific command.
+type ScriptRenderer struct {
+	Delimiter         string
+	CompoundDelimiter string
+	SetDelimiter      func(delimiter string, current string) string
+	current           string
+}
+
+func (r *ScriptRenderer) Statement(stmt string) string {
+	var script string
+	stmt = strings.TrimRight(strings.TrimSpace(stmt), r.Delimiter)
+	delimiter := r.Delimiter
+	if strings.Contains(stmt, r.Delimiter) {
+		delimiter = r.CompoundDelimiter
+	}
+	if delimiter != r.currentDelimiter() {
+		script += r.SetDelimiter(delimiter, r.currentDelimiter()) + "\n"
+		r.current = delimiter
+	}
+
+	return script + stmt + delimiter + "\n"
+}
+
+func (r *ScriptRenderer) Statements(stmts []string) string {
+	var script string
+	for _, stmt := range stmts {
+		script += r.Statement(stmt)
+	}
+
+	return script
+}
+
+// Finish switches the delimiter back, so the script could be included into another one.
+func (r *ScriptRenderer) Finish() string {
+	if r.currentDelimiter() == r.Delimiter {
+		return ""
+	}
+	script := r.SetDelimiter(r.Delimiter, r.currentDelimiter()) + "\n"
+	r.current = r.Delimiter
+
+	return script
+}
+
+func (r *ScriptRenderer) currentDelimiter() string {
+	if r.current == "" {
+		return r.Delimiter
+	}
+
+	return r.current
+}
+
+// ScriptParser splits a script of the native client into statements. Delimiters inside quoted
+// strings and comments are skipped, client commands switching the delimiter are applied and
+// left out of the statements.
+type ScriptParser struct {
+	Delimiter        string
+	Quotes           string
+	BackslashEscapes bool
+	LineComments     []string
+	// DelimiterLine matches a command which takes the rest of the line, e.g. DELIMITER of mysql
+	DelimiterLine *regexp.Regexp
+	// DelimiterStatement matches a command terminated with the current delimiter, e.g. SET TERM of isql
+	DelimiterStatement *regexp.Regexp
+}
+
+type ScriptStatement struct {
+	Line int
+	SQL  string
+}
+
+func (p *ScriptParser) Split(script string) ([]*ScriptStatement, error) {
+	var statements []*ScriptStatement
+	delimiter := p.Delimiter
+	line := 1
+	start, startLine := 0, 1
+	// statement begins at the first position which is not a space or a leading comment
+	begun := false
+	addStatement := func(end int) {
+		stmt := strings.TrimSpace(script[start:end])
+		if stmt == "" {
+			return
+		}
+		if p.DelimiterStatement != nil {
+			if match := p.DelimiterStatement.FindStringSubmatch(stmt); match != nil {
+				delimiter = match[1]
+				return
+			}
+		}
+		statements = append(statements, &ScriptStatement{Line: startLine, SQL: stmt})
+	}
+
+	for i := 0; i < len(script); {
+		c := script[i]
+		if !begun {
+			if c == '\n' || c == ' ' || c == '\t' || c == '\r' {
+				if c == '\n' {
+					line++
+				}
+				i++
+				continue
+			}
+			if p.DelimiterLine != nil {
+				end := strings.IndexByte(script[i:], '\n')
+				if end < 0 {
+					end = len(script) - i
+				}
+				if match := p.DelimiterLine.FindStringSubmatch(script[i : i+end]); match != nil {
+					delimiter = match[1]
+					i += end
+					continue
+				}
+			}
+		}
+		if comment := p.lineComment(script[i:]); comment != "" {
+			end := strings.IndexByte(script[i:], '\n')
+			if end < 0 {
+				end = len(script) - i
+			}
+			i += end
+			continue
+		}
+		// conditional comments of MySQL are executed, so they are kept as statements
+		if strings.HasPrefix(script[i:], "/*") && (begun || !strings.HasPrefix(script[i:], "/*!")) {
+			end := strings.Index(script[i+2:], "*/")
+			if end < 0 {
+				return nil, fmt.Errorf("Unterminated comment at line %d", line)
+			}
+			line += strings.Count(script[i:i+2+end+2], "\n")
+			i += 2 + end + 2
+			continue
+		}
+		if !begun {
+			begun = true
+			start, startLine = i, line
+		}
+		if strings.IndexByte(p.Quotes, c) >= 0 {
+			end, err := p.quoteEnd(script, i)
+			if err != nil {
+				return nil, fmt.Errorf("%s at line %d", err, line)
+			}
+			line += strings.Count(script[i:end], "\n")
+			i = end
+			continue
+		}
+		if strings.HasPrefix(script[i:], delimiter) {
+			addStatement(i)
+			begun = false
+			i += len(delimiter)
+			continue
+		}
+		if c == '\n' {
+			line++
+		}
+		i++
+	}
+	if begun {
+		addStatement(len(script))
+	}
+
+	return statements, nil
+}
+
+func (p *ScriptParser) lineComment(script string) string {
+	for _, comment := range p.LineComments {
+		if strings.HasPrefix(script, comment) {
+			return comment
+		}
+	}
+
+	return ""
+}
+
+// quoteEnd returns the position after the quoted string or identifier which starts at the position.
+func (p *ScriptParser) quoteEnd(script string, start int) (int, error) {
+	quote := script[start]
+	for i := start + 1; i < len(script); i++ {
+		switch {
+		case script[i] == '\\' && p.BackslashEscapes && quote != '`':
+			i++
+		case script[i] == quote:
+			return i + 1, nil
+		}
+	}
+
+	return 0, fmt.Errorf("Unterminated %c quote", quote)
+}
@@ -0,0 +1,102 @@
+package sqlrog
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func TestScriptParserSplit(t *testing.T) {
+	lineParser := &ScriptParser{
+		Delimiter:        ";",
+		Quotes:           "'\"`",
+		BackslashEscapes: true,
+		LineComments:     []string{"-- ", "#"},
+		DelimiterLine:    regexp.MustCompile(`(?i)^DELIMITER\s+(\S+)`),
+	}
+	statementParser := &ScriptParser{
+		Delimiter:          ";",
+		Quotes:             "'\"",
+		LineComments:       []string{"--"},
+		DelimiterStatement: regexp.MustCompile(`(?i)^SET\s+TERM\s+(\S+)$`),
+	}
+	cases := []struct {
+		name     string
+		parser   *ScriptParser
+		script   string
+		expected []*ScriptStatement
+	}{
+		{
+			name:   "delimiters in quotes and comments",
+			parser: lineParser,
+			script: "-- first; comment\nINSERT INTO t VALUES ('a;b', \"c\\\";\");\n/* skipped; */ # also skipped;\nSELECT `x;y` FROM t",
+			expected: []*ScriptStatement{
+				{Line: 2, SQL: "INSERT INTO t VALUES ('a;b', \"c\\\";\")"},
+				{Line: 4, SQL: "SELECT `x;y` FROM t"},
+			},
+		},
+		{
+			name:   "delimiter line",
+			parser: lineParser,
+			script: "DELIMITER //\nCREATE PROCEDURE p()\nBEGIN\n  SELECT 1;\nEND//\nDELIMITER ;\nCALL p();",
+			expected: []*ScriptStatement{
+				{Line: 2, SQL: "CREATE PROCEDURE p()\nBEGIN\n  SELECT 1;\nEND"},
+				{Line: 7, SQL: "CALL p()"},
+			},
+		},
+		{
+			name:   "conditional comment",
+			parser: lineParser,
+			script: "/*!40101 SET NAMES utf8 */;\nSELECT 1;",
+			expected: []*ScriptStatement{
+				{Line: 1, SQL: "/*!40101 SET NAMES utf8 */"},
+				{Line: 2, SQL: "SELECT 1"},
+			},
+		},
+		{
+			name:   "delimiter statement",
+			parser: statementParser,
+			script: "SET TERM ^ ;\nEXECUTE BLOCK AS\nBEGIN\n  EXIT;\nEND^\nSET TERM ; ^\nCOMMIT;",
+			expected: []*ScriptStatement{
+				{Line: 2, SQL: "EXECUTE BLOCK AS\nBEGIN\n  EXIT;\nEND"},
+				{Line: 7, SQL: "COMMIT"},
+			},
+		},
+		{
+			name:   "doubled quotes",
+			parser: statementParser,
+			script: "UPDATE t SET s = 'it''s; fine';",
+			expected: []*ScriptStatement{
+				{Line: 1, SQL: "UPDATE t SET s = 'it''s; fine'"},
+			},
+		},
+	}
+	for _, c := range cases {
+		statements, err := c.parser.Split(c.script)
+		if err != nil {
+			t.Errorf("%s: %s\n", c.name, err)
+			continue
+		}
+		if !reflect.DeepEqual(statements, c.expected) {
+			var got []string
+			for _, statement := range statements {
+				got = append(got, fmt.Sprintf("%d: %s", statement.Line, statement.SQL))
+			}
+			t.Errorf("%s: unexpected statements:\n%s\n", c.name, strings.Join(got, "\n---\n"))
+		}
+	}
+}
+
+func TestScriptParserErrors(t *testing.T) {
+	parser := &ScriptParser{Delimiter: ";", Quotes: "'"}
+	for script, expected := range map[string]string{
+		"SELECT 1;\nSELECT 'open;": "Unterminated ' quote at line 2",
+		"SELECT 1;\n/* open\n;":    "Unterminated comment at line 2",
+	} {
+		if _, err := parser.Split(script); err == nil || err.Error() != expected {
+			t.Errorf("Expected error %q for %q, got %v\n", expected, script, err)
+		}
+	}
+}
@@ -0,0 +1,269 @@
+package sqlrog
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+type ValidationError struct {
+	Path    string
+	Message string
+}
+
+func (ve *ValidationError) Error() string {
+	return fmt.Sprintf("%s: %s", ve.Path, ve.Message)
+}
+
+func NewValidationError(path string, format string, args ...interface{}) *ValidationError {
+	return &ValidationError{
+		Path:    path,
+		Message: fmt.Sprintf(format, args...),
+	}
+}
+
+func SortValidationErrors(errs []*ValidationError) {
+	sort.Slice(errs, func(i, j int) bool {
+		if errs[i].Path != errs[j].Path {
+			return errs[i].Path < errs[j].Path
+		}
+		return errs[i].Message < errs[j].Message
+	})
+}
+
+// ValidateFiles checks every element file of the project separately, so broken files are all
+// reported at once instead of stopping on the first one as LoadElementsFromFiles does.
+// Elements of the files which are read are added to the schema for the checks of the engine.
+func (e *CoreEngine) ValidateFiles(appName string, schema ElementSchema, reader ObjectReader) []*ValidationError {
+	var errs []*ValidationError
+	if _, err := os.Stat("./" + appName); os.IsNotExist(err) {
+		return append(errs, NewValidationError("./"+appName, "project folder doesn't exist"))
+	}
+	for _, el := range schema.GetGlobalChildElements() {
+		elType := reflect.TypeOf(el).Elem()
+		dir := "./" + appName + "/" + el.GetPluralTypeName()
+		files, err := ioutil.ReadDir(dir)
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			errs = append(errs, NewValidationError(dir, "folder can't be read: %s", err))
+			continue
+		}
+		names := make(map[string]string)
+		for _, f := range files {
+			path := dir + "/" + f.Name()
+			if !f.IsDir() && IsHookFile(f.Name()) {
+				continue
+			}
+			if f.IsDir() {
+				errs = append(errs, NewValidationError(path, "unexpected folder"))
+				continue
+			}
+			element := reflect.New(elType).Interface().(ElementSchema)
+			data, err := reader.Read(path)
+			if err != nil {
+				errs = append(errs, NewValidationError(path, "file can't be read: %s", err))
+				continue
+			}
+			if err = yaml.Unmarshal(data, element); err != nil {
+				errs = append(errs, NewValidationError(path, "invalid yaml: %s", err))
+				continue
+			}
+			name := strings.Trim(element.GetName(), " ")
+			if name == "" {
+				errs = append(errs, NewValidationError(path, "%s name is empty", el.GetTypeName()))
+				continue
+			}
+			if strings.TrimSuffix(f.Name(), ".yaml") != name {
+				errs = append(errs, NewValidationError(path, "file name doesn't match %s name '%s'", el.GetTypeName(), name))
+			}
+			if other, ok := names[name]; ok {
+				errs = append(errs, NewValidationError(path, "%s '%s' is already defined in %s", el.GetTypeName(), name, other))
+				continue
+			}
+			names[name] = path
+			if err = schema.AddChild(element); err != nil {
+				errs = append(errs, NewValidationError(path, "%s", err))
+			}
+		}
+	}
+
+	return errs
+}
+
+// ValidationTable is the part of a table file which layout is shared by engines, so the checks
+// of columns, indexes and triggers are written once for all of them.
+type ValidationTable struct {
+	Name     string                                 `yaml:"name"`
+	Columns  map[string]*ValidationColumn           `yaml:"columns"`
+	Indexes  map[string]map[string]*ValidationIndex `yaml:"indexes"`
+	Triggers map[string]*ValidationTrigger          `yaml:"triggers"`
+}
+
+type ValidationColumn struct {
+	Name     string `yaml:"name"`
+	Position int    `yaml:"position"`
+}
+
+type ValidationIndex struct {
+	Name         string                           `yaml:"name"`
+	Type         string                           `yaml:"type"`
+	TableName    string                           `yaml:"tablename"`
+	Expression   string                           `yaml:"expression"`
+	Fields       map[string]*ValidationIndexField `yaml:"fields"`
+	SourceTable  string                           `yaml:"sourcetable"`
+	SourceFields map[string]*ValidationIndexField `yaml:"sourcefields"`
+}
+
+type ValidationIndexField struct {
+	Name string `yaml:"name"`
+}
+
+type ValidationTrigger struct {
+	Name      string `yaml:"name"`
+	TableName string `yaml:"tablename"`
+}
+
+// NewValidationTable reads the shared part of the table the same way it's read from its file.
+func NewValidationTable(table ElementSchema) (*ValidationTable, error) {
+	data, err := yaml.Marshal(table)
+	if err != nil {
+		return nil, err
+	}
+	validationTable := &ValidationTable{}
+	if err = yaml.Unmarshal(data, validationTable); err != nil {
+		return nil, err
+	}
+
+	return validationTable, nil
+}
+
+func (t *ValidationTable) HasColumn(name string) bool {
+	_, ok := t.Columns[name]
+
+	return ok
+}
+
+// ValidateTable runs the table checks shared by engines. Indexes of foreignKeyType must refer to columns
+// of another table, indexes of expressionTypes (e.g. CHECK constraints) must have an expression instead of fields.
+func ValidateTable(table ElementSchema, tables map[string]ElementSchema, foreignKeyType string, expressionTypes ...string) []string {
+	t, err := NewValidationTable(table)
+	if err != nil {
+		return []string{fmt.Sprintf("table can't be read: %s", err)}
+	}
+	messages := t.validateColumns()
+	for indexType, indexes := range t.Indexes {
+		for key, index := range indexes {
+			messages = append(messages, index.validate(key, indexType, t)...)
+			if containsString(expressionTypes, indexType) {
+				if index.Expression == "" {
+					messages = append(messages, fmt.Sprintf("%s '%s' has no expression", indexType, key))
+				}
+				continue
+			}
+			messages = append(messages, index.validateFields(key, indexType, t)...)
+			if indexType == foreignKeyType {
+				messages = append(messages, index.validateSource(key, indexType, tables)...)
+			}
+		}
+	}
+	for key, trigger := range t.Triggers {
+		if trigger.Name != key {
+			messages = append(messages, fmt.Sprintf("trigger '%s' has name '%s'", key, trigger.Name))
+		}
+		if trigger.TableName != t.Name {
+			messages = append(messages, fmt.Sprintf("trigger '%s' belongs to table '%s'", key, trigger.TableName))
+		}
+	}
+
+	return messages
+}
+
+func (t *ValidationTable) validateColumns() []string {
+	var messages []string
+	var names []string
+	for name := range t.Columns {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	positions := make(map[int]string)
+	for _, name := range names {
+		column := t.Columns[name]
+		if column.Name != name {
+			messages = append(messages, fmt.Sprintf("column '%s' has name '%s'", name, column.Name))
+		}
+		if other, ok := positions[column.Position]; ok {
+			messages = append(messages, fmt.Sprintf("columns '%s' and '%s' have the same position %d", other, name, column.Position))
+		}
+		positions[column.Position] = name
+	}
+
+	return messages
+}
+
+func (i *ValidationIndex) validate(key string, indexType string, table *ValidationTable) []string {
+	var messages []string
+	if i.Name != key {
+		messages = append(messages, fmt.Sprintf("%s '%s' has name '%s'", indexType, key, i.Name))
+	}
+	if i.Type != indexType {
+		messages = append(messages, fmt.Sprintf("%s '%s' has type '%s'", indexType, key, i.Type))
+	}
+	if i.TableName != table.Name {
+		messages = append(messages, fmt.Sprintf("%s '%s' belongs to table '%s'", indexType, key, i.TableName))
+	}
+
+	return messages
+}
+
+func (i *ValidationIndex) validateFields(key string, indexType string, table *ValidationTable) []string {
+	var messages []string
+	if len(i.Fields) == 0 {
+		messages = append(messages, fmt.Sprintf("%s '%s' has no fields", indexType, key))
+	}
+	for _, field := range i.Fields {
+		if !table.HasColumn(field.Name) {
+			messages = append(messages, fmt.Sprintf("%s '%s' refers to unknown column '%s'", indexType, key, field.Name))
+		}
+	}
+
+	return messages
+}
+
+func (i *ValidationIndex) validateSource(key string, indexType string, tables map[string]ElementSchema) []string {
+	var messages []string
+	element, ok := tables[i.SourceTable]
+	if !ok {
+		return append(messages, fmt.Sprintf("%s '%s' refers to unknown table '%s'", indexType, key, i.SourceTable))
+	}
+	sourceTable, err := NewValidationTable(element)
+	if err != nil {
+		return append(messages, fmt.Sprintf("%s '%s' refers to table '%s' which can't be read: %s", indexType, key, i.SourceTable, err))
+	}
+	for _, field := range i.SourceFields {
+		if !sourceTable.HasColumn(field.Name) {
+			messages = append(messages, fmt.Sprintf("%s '%s' refers to unknown column '%s.%s'", indexType, key, i.SourceTable, field.Name))
+		}
+	}
+	if len(i.Fields) != len(i.SourceFields) {
+		messages = append(messages, fmt.Sprintf("%s '%s' has %d fields but refers to %d", indexType, key, len(i.Fields), len(i.SourceFields)))
+	}
+
+	return messages
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
+}